@@ -0,0 +1,44 @@
+//go:build gofuzz
+// +build gofuzz
+
+package dns
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// Fuzz is the go-fuzz entry point for this tree. The request that asked for
+// it wanted Fuzz to call NewRR(string(data)), but NewRR and the text
+// tokenizer it depends on aren't part of this snapshot (see zlexer's doc
+// comment) — there is no ParseZone/NewRR here to call into. Instead Fuzz
+// drives the two parsing paths in this tree that take attacker-controlled
+// bytes directly, without needing that tokenizer: the RFC 3597 "\# length
+// hex" rdata path (parseUnknownRdata, fed through the same chan lex the
+// real tokenizer would produce) and SVCB SvcParamValue comma-escaping
+// (splitEscapedList/unescapeSVCBValue).
+func Fuzz(data []byte) int {
+	ret := 0
+
+	for _, part := range splitEscapedList(string(data)) {
+		unescapeSVCBValue(part)
+		ret = 1
+	}
+
+	c := make(chan lex, 8)
+	c <- lex{value: _STRING, token: `\#`}
+	c <- lex{value: _BLANK}
+	c <- lex{value: _STRING, token: strconv.Itoa(len(data))}
+	c <- lex{value: _BLANK}
+	c <- lex{value: _STRING, token: hex.EncodeToString(data)}
+	c <- lex{value: _NEWLINE}
+	c <- lex{value: _EOF}
+	close(c)
+
+	h := RR_Header{Name: "fuzz.example.", Rrtype: TypeTXT, Class: ClassINET, Ttl: 3600}
+	if rr, perr, _ := parseUnknownRdata(h, newZlexer(c), "fuzz"); perr == nil && rr != nil {
+		ret = 1
+	}
+
+	return ret
+}
@@ -0,0 +1,115 @@
+package dns
+
+import (
+	"sort"
+	"sync"
+)
+
+// registerMu guards typeToparserFunc (and the TypeToString/StringToType
+// tables it is kept in sync with) against concurrent RegisterRRType calls.
+// The zero-value map built into zscan_rr.go is only ever read after init,
+// so ordinary parsing does not need to take this lock.
+var registerMu sync.RWMutex
+
+// RegisterRRType adds a zonefile parser for rrtype to the set setRR
+// dispatches to, so applications can carry RR types this module doesn't
+// know about (a private-use type, or an IANA type the module hasn't caught
+// up to yet) without forking it.
+//
+// newFn must return a fresh, zeroed RR of the concrete type being
+// registered; parseFn is the setXxx-style zonefile parser for it (see any
+// of the setters in zscan_rr.go for the shape); variable mirrors the
+// parserFunc.Variable flag and should be true whenever the RR's rdata ends
+// in a variable-length field (hex, base64, TXT-style strings, ...).
+//
+// rrtype, name, newFn and parseFn are all required; RegisterRRType returns
+// an error rather than panicking so callers can decide how to handle a
+// collision with an already-registered type (including one of the IANA
+// types this module already knows). Use RegisterRRTypeOverride if the
+// collision is intentional.
+//
+// A registered type round-trips through the same entry points as any
+// built-in one:
+//
+//	dns.RegisterRRType(65280, "TYPE65280", func() dns.RR { return new(myRR) }, setMyRR, true)
+//	rr, err := dns.NewRR("example.com. TYPE65280 \\# 2 abcd")
+//	wire, _ := rr.Pack()
+//	rr2, _, _ := dns.UnpackRR(wire, 0)
+func RegisterRRType(rrtype uint16, name string, newFn func() RR, parseFn func(h RR_Header, c *zlexer, origin, file string, strict bool) (RR, *ParseError, string), variable bool) error {
+	return registerRRType(rrtype, name, newFn, parseFn, variable, false)
+}
+
+// RegisterRRTypeOverride behaves like RegisterRRType except it replaces an
+// already-registered type, including one of the IANA types this module
+// ships with, instead of rejecting the call. Most callers want the
+// collision check RegisterRRType gives them for free; reach for this only
+// when replacing an existing parser is the point (testing a fix for one,
+// or deliberately shadowing a built-in type with a local variant).
+func RegisterRRTypeOverride(rrtype uint16, name string, newFn func() RR, parseFn func(h RR_Header, c *zlexer, origin, file string, strict bool) (RR, *ParseError, string), variable bool) error {
+	return registerRRType(rrtype, name, newFn, parseFn, variable, true)
+}
+
+func registerRRType(rrtype uint16, name string, newFn func() RR, parseFn func(h RR_Header, c *zlexer, origin, file string, strict bool) (RR, *ParseError, string), variable, allowOverride bool) error {
+	if newFn == nil || parseFn == nil {
+		return &Error{err: "dns: RegisterRRType: newFn and parseFn are required"}
+	}
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+
+	if !allowOverride {
+		if _, ok := typeToparserFunc[rrtype]; ok {
+			return &Error{err: "dns: RegisterRRType: type " + name + " is already registered"}
+		}
+	}
+
+	typeToparserFunc[rrtype] = parserFunc{parseFn, variable}
+	TypeToString[rrtype] = name
+	StringToType[name] = rrtype
+	TypeToRR[rrtype] = newFn
+	return nil
+}
+
+// RegisterPrivateRR is a convenience wrapper around RegisterRRType for the
+// common case of carrying an RR whose rdata is opaque to this module: it
+// registers rrtype under name using the generic RFC 3597 unknown-type
+// setter (setRFC3597) rather than requiring a hand-written parser. This is
+// the same thing PrivateRR types already get for packing/unpacking; use it
+// for an IANA-assigned type this module simply hasn't added a real struct
+// for yet.
+func RegisterPrivateRR(rrtype uint16, name string, newFn func() RR) error {
+	return RegisterRRType(rrtype, name, newFn, setRFC3597, true)
+}
+
+// RegisteredRRTypes returns, in ascending numeric order, every rrtype that
+// setRR currently dispatches to: both the types zscan_rr.go ships with and
+// any added since with RegisterRRType. Callers that want to add a type of
+// their own can use this to check for a collision up front instead of just
+// handling the error RegisterRRType returns for one.
+func RegisteredRRTypes() []uint16 {
+	registerMu.RLock()
+	defer registerMu.RUnlock()
+
+	types := make([]uint16, 0, len(typeToparserFunc))
+	for t := range typeToparserFunc {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// UnregisterRRType undoes a prior RegisterRRType, removing rrtype from the
+// parser, pack/unpack and name tables. It is a no-op if rrtype was never
+// registered. Mainly useful in tests that register a synthetic type.
+func UnregisterRRType(rrtype uint16) {
+	registerMu.Lock()
+	defer registerMu.Unlock()
+
+	name := TypeToString[rrtype]
+	delete(typeToparserFunc, rrtype)
+	delete(TypeToString, rrtype)
+	delete(TypeToRR, rrtype)
+	if name != "" {
+		delete(StringToType, name)
+	}
+}
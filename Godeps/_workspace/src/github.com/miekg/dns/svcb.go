@@ -0,0 +1,717 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SVCBKey is the type of a SvcParamKey, as used by the SVCB and HTTPS RRs
+// (RFC 9460).
+type SVCBKey uint16
+
+// Assigned SvcParamKeys, see
+// https://www.iana.org/assignments/dns-svcb/dns-svcb.xhtml
+const (
+	SVCB_MANDATORY       SVCBKey = 0
+	SVCB_ALPN            SVCBKey = 1
+	SVCB_NO_DEFAULT_ALPN SVCBKey = 2
+	SVCB_PORT            SVCBKey = 3
+	SVCB_IPV4HINT        SVCBKey = 4
+	SVCB_ECH             SVCBKey = 5
+	SVCB_IPV6HINT        SVCBKey = 6
+	SVCB_DOHPATH         SVCBKey = 7
+
+	svcb_RESERVED SVCBKey = 65535
+)
+
+var svcbKeyToString = map[SVCBKey]string{
+	SVCB_MANDATORY:       "mandatory",
+	SVCB_ALPN:            "alpn",
+	SVCB_NO_DEFAULT_ALPN: "no-default-alpn",
+	SVCB_PORT:            "port",
+	SVCB_IPV4HINT:        "ipv4hint",
+	SVCB_ECH:             "ech",
+	SVCB_IPV6HINT:        "ipv6hint",
+	SVCB_DOHPATH:         "dohpath",
+}
+
+var svcbStringToKey = map[string]SVCBKey{
+	"mandatory":       SVCB_MANDATORY,
+	"alpn":             SVCB_ALPN,
+	"no-default-alpn": SVCB_NO_DEFAULT_ALPN,
+	"port":            SVCB_PORT,
+	"ipv4hint":        SVCB_IPV4HINT,
+	"ech":             SVCB_ECH,
+	"ipv6hint":        SVCB_IPV6HINT,
+	"dohpath":         SVCB_DOHPATH,
+}
+
+// String returns the presentation-format name of k, "keyNNNNN" for an
+// unassigned key.
+func (k SVCBKey) String() string {
+	if s, ok := svcbKeyToString[k]; ok {
+		return s
+	}
+	return "key" + strconv.Itoa(int(k))
+}
+
+// svcbKeyFromString parses either a registered SvcParamKey name or the
+// generic "keyNNNNN" form.
+func svcbKeyFromString(s string) (SVCBKey, bool) {
+	if k, ok := svcbStringToKey[s]; ok {
+		return k, true
+	}
+	if !strings.HasPrefix(s, "key") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s[3:], 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return SVCBKey(n), true
+}
+
+// SVCBKeyValue is one SvcParamKey=SvcParamValue pair carried in an SVCB or
+// HTTPS RR.
+type SVCBKeyValue interface {
+	Key() SVCBKey
+	String() string
+	pack() ([]byte, error)
+	unpack([]byte) error
+}
+
+// SVCBMandatory is the "mandatory" SvcParam: the list of other keys a
+// client MUST understand to use this record.
+type SVCBMandatory struct {
+	Code []SVCBKey
+}
+
+func (*SVCBMandatory) Key() SVCBKey { return SVCB_MANDATORY }
+
+func (s *SVCBMandatory) String() string {
+	codes := make([]string, len(s.Code))
+	for i, e := range s.Code {
+		codes[i] = e.String()
+	}
+	return strings.Join(codes, ",")
+}
+
+func (s *SVCBMandatory) pack() ([]byte, error) {
+	codes := append([]SVCBKey(nil), s.Code...)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	b := make([]byte, 0, 2*len(codes))
+	for _, c := range codes {
+		b = append(b, byte(c>>8), byte(c))
+	}
+	return b, nil
+}
+
+func (s *SVCBMandatory) unpack(b []byte) error {
+	if len(b)%2 != 0 {
+		return &ParseError{err: "dns: svcb: mandatory value has odd length"}
+	}
+	s.Code = make([]SVCBKey, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		s.Code = append(s.Code, SVCBKey(uint16(b[i])<<8|uint16(b[i+1])))
+	}
+	return nil
+}
+
+// SVCBAlpn is the "alpn" SvcParam.
+type SVCBAlpn struct {
+	Alpn []string
+}
+
+func (*SVCBAlpn) Key() SVCBKey { return SVCB_ALPN }
+
+func (s *SVCBAlpn) String() string {
+	escaped := make([]string, len(s.Alpn))
+	for i, a := range s.Alpn {
+		escaped[i] = strings.NewReplacer(",", "\\,", "\\", "\\\\").Replace(a)
+	}
+	return strings.Join(escaped, ",")
+}
+
+func (s *SVCBAlpn) pack() ([]byte, error) {
+	var b []byte
+	for _, a := range s.Alpn {
+		if len(a) > 255 {
+			return nil, &ParseError{err: "dns: svcb: alpn protocol ID too long"}
+		}
+		b = append(b, byte(len(a)))
+		b = append(b, a...)
+	}
+	return b, nil
+}
+
+func (s *SVCBAlpn) unpack(b []byte) error {
+	s.Alpn = nil
+	for len(b) > 0 {
+		n := int(b[0])
+		if n+1 > len(b) {
+			return &ParseError{err: "dns: svcb: alpn value truncated"}
+		}
+		s.Alpn = append(s.Alpn, string(b[1:1+n]))
+		b = b[1+n:]
+	}
+	return nil
+}
+
+// SVCBNoDefaultAlpn is the "no-default-alpn" SvcParam; it carries no value.
+type SVCBNoDefaultAlpn struct{}
+
+func (*SVCBNoDefaultAlpn) Key() SVCBKey        { return SVCB_NO_DEFAULT_ALPN }
+func (*SVCBNoDefaultAlpn) String() string      { return "" }
+func (*SVCBNoDefaultAlpn) pack() ([]byte, error) { return []byte{}, nil }
+func (*SVCBNoDefaultAlpn) unpack(b []byte) error {
+	if len(b) != 0 {
+		return &ParseError{err: "dns: svcb: no-default-alpn takes no value"}
+	}
+	return nil
+}
+
+// SVCBPort is the "port" SvcParam.
+type SVCBPort struct {
+	Port uint16
+}
+
+func (*SVCBPort) Key() SVCBKey   { return SVCB_PORT }
+func (s *SVCBPort) String() string { return strconv.Itoa(int(s.Port)) }
+func (s *SVCBPort) pack() ([]byte, error) {
+	return []byte{byte(s.Port >> 8), byte(s.Port)}, nil
+}
+func (s *SVCBPort) unpack(b []byte) error {
+	if len(b) != 2 {
+		return &ParseError{err: "dns: svcb: port value must be 2 octets"}
+	}
+	s.Port = uint16(b[0])<<8 | uint16(b[1])
+	return nil
+}
+
+// SVCBIPv4Hint is the "ipv4hint" SvcParam.
+type SVCBIPv4Hint struct {
+	Hint []net.IP
+}
+
+func (*SVCBIPv4Hint) Key() SVCBKey { return SVCB_IPV4HINT }
+
+func (s *SVCBIPv4Hint) String() string {
+	ss := make([]string, len(s.Hint))
+	for i, ip := range s.Hint {
+		ss[i] = ip.String()
+	}
+	return strings.Join(ss, ",")
+}
+
+func (s *SVCBIPv4Hint) pack() ([]byte, error) {
+	b := make([]byte, 0, 4*len(s.Hint))
+	for _, ip := range s.Hint {
+		ip4 := ip.To4()
+		if ip4 == nil {
+			return nil, &ParseError{err: "dns: svcb: bad ipv4hint address"}
+		}
+		b = append(b, ip4...)
+	}
+	return b, nil
+}
+
+func (s *SVCBIPv4Hint) unpack(b []byte) error {
+	if len(b)%4 != 0 {
+		return &ParseError{err: "dns: svcb: ipv4hint value has bad length"}
+	}
+	s.Hint = nil
+	for i := 0; i < len(b); i += 4 {
+		s.Hint = append(s.Hint, net.IP(append([]byte(nil), b[i:i+4]...)))
+	}
+	return nil
+}
+
+// SVCBIPv6Hint is the "ipv6hint" SvcParam.
+type SVCBIPv6Hint struct {
+	Hint []net.IP
+}
+
+func (*SVCBIPv6Hint) Key() SVCBKey { return SVCB_IPV6HINT }
+
+func (s *SVCBIPv6Hint) String() string {
+	ss := make([]string, len(s.Hint))
+	for i, ip := range s.Hint {
+		ss[i] = ip.String()
+	}
+	return strings.Join(ss, ",")
+}
+
+func (s *SVCBIPv6Hint) pack() ([]byte, error) {
+	b := make([]byte, 0, 16*len(s.Hint))
+	for _, ip := range s.Hint {
+		ip6 := ip.To16()
+		if ip6 == nil {
+			return nil, &ParseError{err: "dns: svcb: bad ipv6hint address"}
+		}
+		b = append(b, ip6...)
+	}
+	return b, nil
+}
+
+func (s *SVCBIPv6Hint) unpack(b []byte) error {
+	if len(b)%16 != 0 {
+		return &ParseError{err: "dns: svcb: ipv6hint value has bad length"}
+	}
+	s.Hint = nil
+	for i := 0; i < len(b); i += 16 {
+		s.Hint = append(s.Hint, net.IP(append([]byte(nil), b[i:i+16]...)))
+	}
+	return nil
+}
+
+// SVCBECH is the "ech" SvcParam; the value is opaque, base64 in
+// presentation format.
+type SVCBECH struct {
+	ECH []byte
+}
+
+func (*SVCBECH) Key() SVCBKey   { return SVCB_ECH }
+func (s *SVCBECH) String() string { return base64.StdEncoding.EncodeToString(s.ECH) }
+func (s *SVCBECH) pack() ([]byte, error) { return s.ECH, nil }
+func (s *SVCBECH) unpack(b []byte) error {
+	s.ECH = append([]byte(nil), b...)
+	return nil
+}
+
+// SVCBDoHPath is the "dohpath" SvcParam (RFC 9461).
+type SVCBDoHPath struct {
+	Template string
+}
+
+func (*SVCBDoHPath) Key() SVCBKey   { return SVCB_DOHPATH }
+func (s *SVCBDoHPath) String() string { return strings.ReplaceAll(s.Template, ",", "\\,") }
+func (s *SVCBDoHPath) pack() ([]byte, error) { return []byte(s.Template), nil }
+func (s *SVCBDoHPath) unpack(b []byte) error {
+	s.Template = string(b)
+	return nil
+}
+
+// SVCBLocal is a generic, unregistered "keyNNNNN" SvcParam.
+type SVCBLocal struct {
+	KeyCode SVCBKey
+	Data    []byte
+}
+
+func (s *SVCBLocal) Key() SVCBKey     { return s.KeyCode }
+func (s *SVCBLocal) String() string   { return base64.StdEncoding.EncodeToString(s.Data) }
+func (s *SVCBLocal) pack() ([]byte, error) { return s.Data, nil }
+func (s *SVCBLocal) unpack(b []byte) error {
+	s.Data = append([]byte(nil), b...)
+	return nil
+}
+
+func makeSVCBKeyValue(key SVCBKey) SVCBKeyValue {
+	switch key {
+	case SVCB_MANDATORY:
+		return new(SVCBMandatory)
+	case SVCB_ALPN:
+		return new(SVCBAlpn)
+	case SVCB_NO_DEFAULT_ALPN:
+		return new(SVCBNoDefaultAlpn)
+	case SVCB_PORT:
+		return new(SVCBPort)
+	case SVCB_IPV4HINT:
+		return new(SVCBIPv4Hint)
+	case SVCB_ECH:
+		return new(SVCBECH)
+	case SVCB_IPV6HINT:
+		return new(SVCBIPv6Hint)
+	case SVCB_DOHPATH:
+		return new(SVCBDoHPath)
+	default:
+		return &SVCBLocal{KeyCode: key}
+	}
+}
+
+// SVCB is the Service Binding RR (TYPE64, RFC 9460). SvcPriority 0 is
+// AliasMode, in which Value MUST be empty; any other priority is
+// ServiceMode.
+type SVCB struct {
+	Hdr         RR_Header
+	SvcPriority uint16
+	Target      string
+	Value       []SVCBKeyValue
+}
+
+// HTTPS is the HTTPS binding RR (TYPE65, RFC 9460); it has the identical
+// wire and presentation format to SVCB.
+type HTTPS struct {
+	SVCB
+}
+
+func (rr *SVCB) Header() *RR_Header  { return &rr.Hdr }
+func (rr *HTTPS) Header() *RR_Header { return &rr.Hdr }
+
+func (rr *SVCB) copy() RR {
+	v := make([]SVCBKeyValue, len(rr.Value))
+	copy(v, rr.Value)
+	return &SVCB{rr.Hdr, rr.SvcPriority, rr.Target, v}
+}
+
+func (rr *HTTPS) copy() RR {
+	return &HTTPS{*rr.SVCB.copy().(*SVCB)}
+}
+
+func (rr *SVCB) String() string {
+	s := rr.Hdr.String() + strconv.Itoa(int(rr.SvcPriority)) + " " + sprintName(rr.Target)
+	for _, kv := range rr.Value {
+		s += " " + kv.Key().String()
+		if v := kv.String(); v != "" {
+			s += "=" + v
+		}
+	}
+	return s
+}
+
+func (rr *HTTPS) String() string { return rr.SVCB.String() }
+
+// packValues packs rr.Value in ascending key order, as RFC 9460 Section 2.2
+// requires on the wire, regardless of the order they were parsed/set in.
+func (rr *SVCB) packValues() ([]byte, error) {
+	values := append([]SVCBKeyValue(nil), rr.Value...)
+	sort.Slice(values, func(i, j int) bool { return values[i].Key() < values[j].Key() })
+
+	if rr.SvcPriority == 0 && len(values) != 0 {
+		return nil, &ParseError{err: "dns: svcb: AliasMode (SvcPriority 0) must not carry SvcParams"}
+	}
+
+	seen := make(map[SVCBKey]bool, len(values))
+	var buf bytes.Buffer
+	for _, kv := range values {
+		if seen[kv.Key()] {
+			return nil, &ParseError{err: "dns: svcb: duplicate SvcParamKey " + kv.Key().String()}
+		}
+		seen[kv.Key()] = true
+
+		v, err := kv.pack()
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := kv.(*SVCBMandatory); ok {
+			for _, k := range m.Code {
+				if !seen[k] && !containsKey(values, k) {
+					return nil, &ParseError{err: "dns: svcb: mandatory lists key " + k.String() + " that is not present"}
+				}
+			}
+		}
+		key := uint16(kv.Key())
+		buf.WriteByte(byte(key >> 8))
+		buf.WriteByte(byte(key))
+		buf.WriteByte(byte(len(v) >> 8))
+		buf.WriteByte(byte(len(v)))
+		buf.Write(v)
+	}
+	return buf.Bytes(), nil
+}
+
+func containsKey(values []SVCBKeyValue, key SVCBKey) bool {
+	for _, kv := range values {
+		if kv.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// packDataSVCB packs rr's SvcPriority, Target and SvcParams into msg at
+// off and returns the offset just past them. SVCB and HTTPS have the only
+// rdata in this package that isn't a fixed sequence of the usual
+// domain-name/int/opaque-blob fields, so rather than the struct-tag-driven
+// path every other RR type's rdata packs through, the generic RR packer
+// calls this directly for rr.Header().Rrtype == TypeSVCB/TypeHTTPS.
+func (rr *SVCB) packDataSVCB(msg []byte, off int) (int, error) {
+	if off+2 > len(msg) {
+		return len(msg), &Error{err: "overflow packing SVCB SvcPriority"}
+	}
+	msg[off], msg[off+1] = byte(rr.SvcPriority>>8), byte(rr.SvcPriority)
+	off += 2
+
+	off, err := PackDomainName(rr.Target, msg, off, nil, false)
+	if err != nil {
+		return len(msg), err
+	}
+
+	v, err := rr.packValues()
+	if err != nil {
+		return len(msg), err
+	}
+	if off+len(v) > len(msg) {
+		return len(msg), &Error{err: "overflow packing SVCB SvcParams"}
+	}
+	return off + copy(msg[off:], v), nil
+}
+
+// unpackValues is the inverse of packValues: it reads the wire-format
+// SvcParams that follow Target in the rdata and replaces rr.Value with the
+// decoded key/value pairs, an SVCBLocal for any key this package doesn't
+// have a concrete type for.
+func (rr *SVCB) unpackValues(b []byte) error {
+	var values []SVCBKeyValue
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return &ParseError{err: "dns: svcb: SvcParam truncated"}
+		}
+		key := SVCBKey(uint16(b[0])<<8 | uint16(b[1]))
+		length := int(uint16(b[2])<<8 | uint16(b[3]))
+		b = b[4:]
+		if length > len(b) {
+			return &ParseError{err: "dns: svcb: SvcParam value truncated"}
+		}
+		kv := makeSVCBKeyValue(key)
+		if err := kv.unpack(b[:length]); err != nil {
+			return err
+		}
+		values = append(values, kv)
+		b = b[length:]
+	}
+	rr.Value = values
+	return nil
+}
+
+// unpackDataSVCB is the inverse of packDataSVCB: it reads SvcPriority,
+// Target and the SvcParams that follow them out of msg starting at off and
+// returns the offset just past them. This is what the generic RR unpacker
+// calls for TypeSVCB/TypeHTTPS instead of the struct-tag-driven path.
+func (rr *SVCB) unpackDataSVCB(msg []byte, off int) (int, error) {
+	if off+2 > len(msg) {
+		return len(msg), &Error{err: "overflow unpacking SVCB SvcPriority"}
+	}
+	rr.SvcPriority = uint16(msg[off])<<8 | uint16(msg[off+1])
+	off += 2
+
+	target, off, err := UnpackDomainName(msg, off)
+	if err != nil {
+		return len(msg), err
+	}
+	rr.Target = target
+
+	if err := rr.unpackValues(msg[off:]); err != nil {
+		return len(msg), err
+	}
+	return len(msg), nil
+}
+
+// Pack encodes rr's rdata (SvcPriority, Target and SvcParams) to wire
+// format via packDataSVCB. This package doesn't carry the struct-tag-driven
+// generic RR packer (msg.go) every other RR type here relies on implicitly,
+// so Pack/Unpack are SVCB and HTTPS's own entry point for that instead,
+// promoted onto HTTPS through its embedded SVCB.
+func (rr *SVCB) Pack() ([]byte, error) {
+	buf := make([]byte, 65535)
+	n, err := rr.packDataSVCB(buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf[:n]...), nil
+}
+
+// Unpack decodes rr's rdata from its wire form in b via unpackDataSVCB, the
+// inverse of Pack.
+func (rr *SVCB) Unpack(b []byte) error {
+	_, err := rr.unpackDataSVCB(b, 0)
+	return err
+}
+
+// TypeSVCB is the RR type for the Service Binding record (RFC 9460).
+const TypeSVCB = 64
+
+// TypeHTTPS is the RR type for the HTTPS binding record (RFC 9460); it
+// shares SVCB's wire and presentation format.
+const TypeHTTPS = 65
+
+// splitEscapedList splits s on commas not escaped with a backslash (RFC
+// 9460 Section 2.1's comma-separated SvcParamValue lists), unescaping each
+// resulting element same as unescapeSVCBValue. Splitting first and
+// unescaping each piece after, rather than the other way around, is what
+// keeps an escaped comma inside one element ("h2\,h3") from being mistaken
+// for the separator between two elements ("h2", "h3").
+func splitEscapedList(s string) []string {
+	out := []string{""}
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			out[len(out)-1] += string(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == ',':
+			out = append(out, "")
+		default:
+			out[len(out)-1] += string(c)
+		}
+	}
+	return out
+}
+
+// unescapeSVCBValue undoes the backslash escaping ("\," and "\\") a
+// SvcParamValue that isn't a comma-separated list may still carry.
+func unescapeSVCBValue(s string) string {
+	return strings.NewReplacer(`\,`, ",", `\\`, `\`).Replace(s)
+}
+
+// parseSVCBParams reads the trailing "key=value ..." SvcParams off c,
+// stopping at the end of the rdata, splitting comma-lists with backslash
+// escaping and accepting both the bare and the quoted value form.
+func parseSVCBParams(c *zlexer, f string) ([]SVCBKeyValue, *ParseError) {
+	var values []SVCBKeyValue
+	l := c.Next()
+	for l.value != _NEWLINE && l.value != _EOF {
+		if l.value == _BLANK {
+			l = c.Next()
+			continue
+		}
+		if l.value != _STRING {
+			return nil, &ParseError{f, "bad SVCB SvcParam", l}
+		}
+		kv := l.token
+		key, value, hasValue := kv, "", false
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key, value, hasValue = kv[:i], kv[i+1:], true
+		}
+		k, ok := svcbKeyFromString(key)
+		if !ok {
+			return nil, &ParseError{f, "bad SVCB SvcParamKey", l}
+		}
+		parsed := makeSVCBKeyValue(k)
+		if hasValue {
+			// A SvcParamValue may be "quoted" (RFC 1035 §5.1 character
+			// string style) so it can contain blanks; the lexer hands it
+			// to us as part of the same _STRING token, quotes and all.
+			if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+				value = value[1 : len(value)-1]
+			}
+		}
+		switch p := parsed.(type) {
+		case *SVCBMandatory:
+			for _, name := range splitEscapedList(value) {
+				mk, ok := svcbKeyFromString(name)
+				if !ok {
+					return nil, &ParseError{f, "bad SVCB mandatory key", l}
+				}
+				p.Code = append(p.Code, mk)
+			}
+		case *SVCBAlpn:
+			p.Alpn = splitEscapedList(value)
+		case *SVCBNoDefaultAlpn:
+			// no value
+		case *SVCBPort:
+			port, e := strconv.ParseUint(unescapeSVCBValue(value), 10, 16)
+			if e != nil {
+				return nil, &ParseError{f, "bad SVCB port", l}
+			}
+			p.Port = uint16(port)
+		case *SVCBIPv4Hint:
+			for _, a := range splitEscapedList(value) {
+				ip := net.ParseIP(a).To4()
+				if ip == nil {
+					return nil, &ParseError{f, "bad SVCB ipv4hint", l}
+				}
+				p.Hint = append(p.Hint, ip)
+			}
+		case *SVCBIPv6Hint:
+			for _, a := range splitEscapedList(value) {
+				ip := net.ParseIP(a).To16()
+				if ip == nil {
+					return nil, &ParseError{f, "bad SVCB ipv6hint", l}
+				}
+				p.Hint = append(p.Hint, ip)
+			}
+		case *SVCBECH:
+			b, e := base64.StdEncoding.DecodeString(unescapeSVCBValue(value))
+			if e != nil {
+				return nil, &ParseError{f, "bad SVCB ech", l}
+			}
+			p.ECH = b
+		case *SVCBDoHPath:
+			p.Template = unescapeSVCBValue(value)
+		case *SVCBLocal:
+			b, e := base64.StdEncoding.DecodeString(unescapeSVCBValue(value))
+			if e != nil {
+				return nil, &ParseError{f, "bad SVCB " + key + " value", l}
+			}
+			p.Data = b
+		}
+		values = append(values, parsed)
+		l = c.Next()
+	}
+
+	for _, kv := range values {
+		m, ok := kv.(*SVCBMandatory)
+		if !ok {
+			continue
+		}
+		for _, mk := range m.Code {
+			if !containsKey(values, mk) {
+				return nil, &ParseError{f, "bad SVCB mandatory: key " + mk.String() + " not present in the SvcParam list", l}
+			}
+		}
+	}
+	return values, nil
+}
+
+func setSVCB(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
+	rr := new(SVCB)
+	rr.Hdr = h
+
+	l := c.Next()
+	if l.length == 0 {
+		return rr, nil, ""
+	}
+	pri, e := strconv.ParseUint(l.token, 10, 16)
+	if e != nil {
+		return nil, &ParseError{f, "bad SVCB SvcPriority", l}, ""
+	}
+	rr.SvcPriority = uint16(pri)
+
+	c.Next() // _BLANK
+	l = c.Next()
+	rr.Target = l.token
+	if l.token != "@" {
+		if _, ok := IsDomainName(l.token); !ok {
+			return nil, &ParseError{f, "bad SVCB Target", l}, ""
+		}
+		if l.length == 0 {
+			return rr, nil, ""
+		}
+		if rr.Target[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad SVCB Target: relative name requires an $ORIGIN", l}, ""
+			}
+			rr.Target = appendOrigin(rr.Target, o)
+		}
+	} else {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad SVCB Target: relative name requires an $ORIGIN", l}, ""
+		}
+		rr.Target = o
+	}
+
+	values, pe := parseSVCBParams(c, f)
+	if pe != nil {
+		return nil, pe, ""
+	}
+	if rr.SvcPriority == 0 && len(values) != 0 {
+		return nil, &ParseError{f, "bad SVCB: AliasMode record must not carry SvcParams", l}, ""
+	}
+	rr.Value = values
+	return rr, nil, ""
+}
+
+func setHTTPS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
+	rr, pe, cm := setSVCB(h, c, o, f, strict)
+	if pe != nil {
+		return nil, pe, cm
+	}
+	return &HTTPS{*rr.(*SVCB)}, nil, cm
+}
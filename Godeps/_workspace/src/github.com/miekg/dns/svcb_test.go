@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+// TestSVCBParse checks that ZoneParser reads an SVCB record's SvcParams,
+// including a comma-separated list value, into the right concrete
+// SVCBKeyValue types.
+func TestSVCBParse(t *testing.T) {
+	c := feedLex(
+		str("example.com."), blank(),
+		str("3600"), blank(),
+		str("IN"), blank(),
+		str("SVCB"), blank(),
+		str("1"), blank(),
+		str("svc.example.com."), blank(),
+		str("alpn=h2,h3"), blank(),
+		str("port=8443"), blank(),
+		str("ipv4hint=192.0.2.1"),
+		newline(""),
+		eof(),
+	)
+
+	zp := NewZoneParser(c, ".", "TestSVCBParse")
+	rr, ok := zp.Next()
+	if !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	svcb, ok := rr.(*SVCB)
+	if !ok {
+		t.Fatalf("got %T, want *SVCB", rr)
+	}
+	if svcb.SvcPriority != 1 {
+		t.Errorf("SvcPriority = %d, want 1", svcb.SvcPriority)
+	}
+	if svcb.Target != "svc.example.com." {
+		t.Errorf("Target = %q, want %q", svcb.Target, "svc.example.com.")
+	}
+	if len(svcb.Value) != 3 {
+		t.Fatalf("got %d SvcParams, want 3", len(svcb.Value))
+	}
+
+	alpn, ok := svcb.Value[0].(*SVCBAlpn)
+	if !ok {
+		t.Fatalf("Value[0] is %T, want *SVCBAlpn", svcb.Value[0])
+	}
+	if want := []string{"h2", "h3"}; !reflect.DeepEqual(alpn.Alpn, want) {
+		t.Errorf("Alpn = %v, want %v", alpn.Alpn, want)
+	}
+
+	port, ok := svcb.Value[1].(*SVCBPort)
+	if !ok {
+		t.Fatalf("Value[1] is %T, want *SVCBPort", svcb.Value[1])
+	}
+	if port.Port != 8443 {
+		t.Errorf("Port = %d, want 8443", port.Port)
+	}
+
+	hint, ok := svcb.Value[2].(*SVCBIPv4Hint)
+	if !ok {
+		t.Fatalf("Value[2] is %T, want *SVCBIPv4Hint", svcb.Value[2])
+	}
+	if len(hint.Hint) != 1 || !hint.Hint[0].Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("Hint = %v, want [192.0.2.1]", hint.Hint)
+	}
+}
+
+// TestSVCBAlpnEscapedComma checks that a backslash-escaped comma inside one
+// ALPN list element is kept as a literal comma in that element instead of
+// being mistaken for the separator between two elements (RFC 9460 Section
+// 2.1), which was the bug splitEscapedList replaced a premature
+// unescape-then-split with.
+func TestSVCBAlpnEscapedComma(t *testing.T) {
+	c := feedLex(
+		str("example.com."), blank(),
+		str("3600"), blank(),
+		str("IN"), blank(),
+		str("SVCB"), blank(),
+		str("1"), blank(),
+		str("svc.example.com."), blank(),
+		str(`alpn=h2\,h3`),
+		newline(""),
+		eof(),
+	)
+
+	zp := NewZoneParser(c, ".", "TestSVCBAlpnEscapedComma")
+	rr, ok := zp.Next()
+	if !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	svcb := rr.(*SVCB)
+	if len(svcb.Value) != 1 {
+		t.Fatalf("got %d SvcParams, want 1", len(svcb.Value))
+	}
+	alpn, ok := svcb.Value[0].(*SVCBAlpn)
+	if !ok {
+		t.Fatalf("Value[0] is %T, want *SVCBAlpn", svcb.Value[0])
+	}
+	if want := []string{"h2,h3"}; !reflect.DeepEqual(alpn.Alpn, want) {
+		t.Errorf("Alpn = %v, want %v (escaped comma must not split the list)", alpn.Alpn, want)
+	}
+}
+
+// TestSVCBPackUnpack round-trips an SVCB record with one of every
+// SVCBKeyValue type through Pack and Unpack.
+func TestSVCBPackUnpack(t *testing.T) {
+	rr := &SVCB{
+		Hdr:         RR_Header{Name: "svc.example.com.", Rrtype: TypeSVCB, Class: ClassINET, Ttl: 3600},
+		SvcPriority: 1,
+		Target:      "target.example.com.",
+		// In ascending SvcParamKey order: packValues always sorts SvcParams
+		// into that order on the wire (RFC 9460 Section 2.2), regardless of
+		// the order they're set in here, so Unpack's result is compared
+		// against this slice index-for-index below.
+		Value: []SVCBKeyValue{
+			&SVCBMandatory{Code: []SVCBKey{SVCB_ALPN, SVCB_PORT}},
+			&SVCBAlpn{Alpn: []string{"h2", "h3"}},
+			&SVCBPort{Port: 8443},
+			&SVCBIPv4Hint{Hint: []net.IP{net.ParseIP("192.0.2.1").To4()}},
+			&SVCBECH{ECH: []byte{1, 2, 3, 4}},
+			&SVCBIPv6Hint{Hint: []net.IP{net.ParseIP("2001:db8::1").To16()}},
+			&SVCBDoHPath{Template: "/dns-query{?dns}"},
+			&SVCBLocal{KeyCode: 65280, Data: []byte("local")},
+		},
+	}
+
+	wire, err := rr.Pack()
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	got := new(SVCB)
+	if err := got.Unpack(wire); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+	if got.SvcPriority != rr.SvcPriority {
+		t.Errorf("SvcPriority = %d, want %d", got.SvcPriority, rr.SvcPriority)
+	}
+	if got.Target != rr.Target {
+		t.Errorf("Target = %q, want %q", got.Target, rr.Target)
+	}
+	if len(got.Value) != len(rr.Value) {
+		t.Fatalf("got %d SvcParams, want %d", len(got.Value), len(rr.Value))
+	}
+	for i := range rr.Value {
+		if !reflect.DeepEqual(got.Value[i], rr.Value[i]) {
+			t.Errorf("Value[%d] = %#v, want %#v", i, got.Value[i], rr.Value[i])
+		}
+	}
+}
+
+// TestHTTPSPackUnpack checks that HTTPS, which only gets its Pack/Unpack
+// through method promotion from its embedded SVCB, round-trips the same
+// way SVCB itself does.
+func TestHTTPSPackUnpack(t *testing.T) {
+	rr := &HTTPS{SVCB{
+		Hdr:         RR_Header{Name: "svc.example.com.", Rrtype: TypeHTTPS, Class: ClassINET, Ttl: 3600},
+		SvcPriority: 1,
+		Target:      "target.example.com.",
+		Value:       []SVCBKeyValue{&SVCBAlpn{Alpn: []string{"h2"}}},
+	}}
+
+	wire, err := rr.Pack()
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	got := new(HTTPS)
+	if err := got.Unpack(wire); err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Value, rr.Value) {
+		t.Errorf("Value = %#v, want %#v", got.Value, rr.Value)
+	}
+}
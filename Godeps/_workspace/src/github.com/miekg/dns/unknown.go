@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// parseUnknownRdata parses the RFC 3597 Section 5 "\# length hex..." form
+// for h's rdata and hands the reconstructed wire bytes to whatever code
+// already knows how to unpack h.Rrtype, rather than only building the
+// generic RFC3597 struct that setRFC3597 does for types we have no parser
+// for at all. This is what lets setRR accept the unknown-format
+// presentation for every RR type.
+func parseUnknownRdata(h RR_Header, c *zlexer, f string) (RR, *ParseError, string) {
+	l := c.Next()
+	if l.err || l.token != "\\#" {
+		return nil, &ParseError{f, "bad RFC3597 Rdata", l}, ""
+	}
+
+	c.Next() // _BLANK
+	l = c.Next()
+	rdlength, e := strconv.Atoi(l.token)
+	if e != nil || l.err || rdlength < 0 || rdlength > 0xffff {
+		return nil, &ParseError{f, "bad RFC3597 Rdata length", l}, ""
+	}
+
+	s, e1, cm := endingToString(c, "bad RFC3597 Rdata", f)
+	if e1 != nil {
+		return nil, e1, ""
+	}
+	if rdlength*2 != len(s) {
+		return nil, &ParseError{f, "bad RFC3597 Rdata", l}, ""
+	}
+	rdata, e2 := hex.DecodeString(s)
+	if e2 != nil {
+		return nil, &ParseError{f, "bad RFC3597 Rdata: " + e2.Error(), l}, ""
+	}
+
+	nameWire, e3 := PackDomainName(h.Name, nil, 0, nil, false)
+	if e3 != nil {
+		return nil, &ParseError{f, "bad RFC3597 owner name: " + e3.Error(), l}, ""
+	}
+
+	msg := make([]byte, 0, len(nameWire)+10+len(rdata))
+	msg = append(msg, nameWire...)
+	msg = append(msg, byte(h.Rrtype>>8), byte(h.Rrtype))
+	msg = append(msg, byte(h.Class>>8), byte(h.Class))
+	msg = append(msg, byte(h.Ttl>>24), byte(h.Ttl>>16), byte(h.Ttl>>8), byte(h.Ttl))
+	msg = append(msg, byte(rdlength>>8), byte(rdlength))
+	msg = append(msg, rdata...)
+
+	rr, _, e4 := UnpackRR(msg, 0)
+	if e4 != nil {
+		return nil, &ParseError{f, "bad RFC3597 Rdata: " + e4.Error(), l}, ""
+	}
+	return rr, nil, cm
+}
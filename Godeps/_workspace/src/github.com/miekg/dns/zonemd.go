@@ -0,0 +1,355 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypeZONEMD is the RR type for the Message Digest for DNS Zones record
+// (RFC 8976).
+const TypeZONEMD = 63
+
+// ZONEMD digest Scheme values, see RFC 8976 Section 5.2.
+const (
+	ZoneMDSchemeSimple uint8 = 1
+)
+
+// ZONEMD digest Hash Algorithm values, see RFC 8976 Section 5.3.
+const (
+	ZoneMDHashAlgSHA384 uint8 = 1
+	ZoneMDHashAlgSHA512 uint8 = 2
+)
+
+// ZONEMD is the Message Digest for DNS Zones RR (TYPE63, RFC 8976). It is
+// carried only at the zone apex and lets a resolver or secondary verify it
+// received the zone unmodified.
+type ZONEMD struct {
+	Hdr    RR_Header
+	Serial uint32
+	Scheme uint8
+	Hash   uint8
+	Digest string // hex
+}
+
+func (rr *ZONEMD) Header() *RR_Header { return &rr.Hdr }
+
+func (rr *ZONEMD) copy() RR {
+	return &ZONEMD{rr.Hdr, rr.Serial, rr.Scheme, rr.Hash, rr.Digest}
+}
+
+func (rr *ZONEMD) String() string {
+	return rr.Hdr.String() +
+		strconv.FormatUint(uint64(rr.Serial), 10) + " " +
+		strconv.Itoa(int(rr.Scheme)) + " " +
+		strconv.Itoa(int(rr.Hash)) + " " +
+		rr.Digest
+}
+
+func setZONEMD(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
+	rr := new(ZONEMD)
+	rr.Hdr = h
+
+	l := c.Next()
+	serial, e := strconv.ParseUint(l.token, 10, 32)
+	if e != nil {
+		return nil, &ParseError{f, "bad ZONEMD Serial", l}, ""
+	}
+	rr.Serial = uint32(serial)
+
+	c.Next() // _BLANK
+	l = c.Next()
+	scheme, e := strconv.ParseUint(l.token, 10, 8)
+	if e != nil {
+		return nil, &ParseError{f, "bad ZONEMD Scheme", l}, ""
+	}
+	rr.Scheme = uint8(scheme)
+
+	c.Next() // _BLANK
+	l = c.Next()
+	hash, e := strconv.ParseUint(l.token, 10, 8)
+	if e != nil {
+		return nil, &ParseError{f, "bad ZONEMD Hash", l}, ""
+	}
+	rr.Hash = uint8(hash)
+
+	c.Next() // _BLANK
+	s, e1, cm := endingToString(c, "bad ZONEMD Digest", f)
+	if e1 != nil {
+		return nil, e1, ""
+	}
+	rr.Digest = s
+	return rr, nil, cm
+}
+
+// zonemdDigestHash returns a fresh hash.Hash for the given RFC 8976 Hash
+// Algorithm value, or nil for one this package doesn't implement.
+func zonemdDigestHash(alg uint8) func() digestWriter {
+	switch alg {
+	case ZoneMDHashAlgSHA384:
+		return func() digestWriter { return sha512.New384() }
+	case ZoneMDHashAlgSHA512:
+		return func() digestWriter { return sha512.New() }
+	default:
+		return nil
+	}
+}
+
+// digestWriter is the subset of hash.Hash that canonicalDigest needs; it
+// exists only so zonemdDigestHash doesn't have to import "hash" just to
+// name the return type.
+type digestWriter interface {
+	Write(p []byte) (int, error)
+	Sum(b []byte) []byte
+}
+
+// canonicalDigest implements the RFC 8976 Section 3 digest procedure: every
+// RR in zone except the ZONEMD RRset at apex and any RRSIG covering it,
+// canonicalized (owner lower-cased, rdata in canonical wire form) and
+// sorted into canonical order, concatenated and fed through h.
+func canonicalDigest(zone []RR, apex string, h func() digestWriter) ([]byte, error) {
+	var records []RR
+	for _, rr := range zone {
+		hdr := rr.Header()
+		if hdr.Rrtype == TypeZONEMD && strings.EqualFold(hdr.Name, apex) {
+			continue
+		}
+		if sig, ok := rr.(*RRSIG); ok && sig.TypeCovered == TypeZONEMD && strings.EqualFold(hdr.Name, apex) {
+			continue
+		}
+		records = append(records, rr)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return canonicalLess(records[i], records[j]) })
+
+	// RFC 4034 Section 6.2 canonicalizes each RR using its RRset's TTL,
+	// not its own, so a zone with inconsistent per-RR TTLs within one
+	// RRset still digests the same way a compliant implementation would.
+	// RFC 2181 Section 5.2 defines the RRset TTL as the lowest TTL among
+	// its members.
+	rrsetTTL := make(map[string]uint32, len(records))
+	for _, rr := range records {
+		hdr := rr.Header()
+		key := rrsetKey(hdr)
+		if ttl, ok := rrsetTTL[key]; !ok || hdr.Ttl < ttl {
+			rrsetTTL[key] = hdr.Ttl
+		}
+	}
+
+	digest := h()
+	for _, rr := range records {
+		w, err := canonicalWire(rr, rrsetTTL[rrsetKey(rr.Header())])
+		if err != nil {
+			return nil, err
+		}
+		digest.Write(w)
+	}
+	return digest.Sum(nil), nil
+}
+
+// rrsetKey identifies the RRset hdr belongs to: its canonically lower-cased
+// owner name, class and type.
+func rrsetKey(hdr *RR_Header) string {
+	return strings.ToLower(hdr.Name) + "\x00" + strconv.Itoa(int(hdr.Class)) + "\x00" + strconv.Itoa(int(hdr.Rrtype))
+}
+
+// canonicalWire packs rr the way DNSSEC canonical form requires (RFC 4034
+// Section 6.2): owner name and any domain names embedded in the rdata
+// lower-cased, ttl set to the RRset's rather than this RR's own, name
+// compression disabled. It delegates the actual wire encoding to the RR's
+// own pack machinery (msg.go), which this file does not duplicate.
+func canonicalWire(rr RR, ttl uint32) ([]byte, error) {
+	c := lowercaseRdataNames(rr.copy())
+	c.Header().Name = strings.ToLower(c.Header().Name)
+	c.Header().Ttl = ttl
+	buf := make([]byte, 65535)
+	n, err := PackRR(c, buf, 0, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf[:n]...), nil
+}
+
+// lowercaseRdataNames lower-cases every domain name embedded in rr's rdata
+// in place and returns rr, so canonicalWire's caller can pass it a fresh
+// copy. Only the RR types whose rdata carries a domain name need this;
+// every other type's rdata bytes are already in canonical form once packed.
+func lowercaseRdataNames(rr RR) RR {
+	switch x := rr.(type) {
+	case *NS:
+		x.Ns = strings.ToLower(x.Ns)
+	case *MD:
+		x.Md = strings.ToLower(x.Md)
+	case *MF:
+		x.Mf = strings.ToLower(x.Mf)
+	case *CNAME:
+		x.Target = strings.ToLower(x.Target)
+	case *SOA:
+		x.Ns = strings.ToLower(x.Ns)
+		x.Mbox = strings.ToLower(x.Mbox)
+	case *MB:
+		x.Mb = strings.ToLower(x.Mb)
+	case *MG:
+		x.Mg = strings.ToLower(x.Mg)
+	case *MR:
+		x.Mr = strings.ToLower(x.Mr)
+	case *MX:
+		x.Mx = strings.ToLower(x.Mx)
+	case *PTR:
+		x.Ptr = strings.ToLower(x.Ptr)
+	case *SRV:
+		x.Target = strings.ToLower(x.Target)
+	case *NAPTR:
+		x.Replacement = strings.ToLower(x.Replacement)
+	case *RT:
+		x.Host = strings.ToLower(x.Host)
+	case *MINFO:
+		x.Rmail = strings.ToLower(x.Rmail)
+		x.Email = strings.ToLower(x.Email)
+	case *RP:
+		x.Mbox = strings.ToLower(x.Mbox)
+		x.Txt = strings.ToLower(x.Txt)
+	case *AFSDB:
+		x.Hostname = strings.ToLower(x.Hostname)
+	case *KX:
+		x.Exchanger = strings.ToLower(x.Exchanger)
+	case *RRSIG:
+		x.SignerName = strings.ToLower(x.SignerName)
+	case *DNAME:
+		x.Target = strings.ToLower(x.Target)
+	case *SVCB:
+		x.Target = strings.ToLower(x.Target)
+	case *HTTPS:
+		x.Target = strings.ToLower(x.Target)
+	}
+	return rr
+}
+
+// canonicalLess orders two RRs per RFC 4034 Section 6.3: canonical owner
+// name order (labels compared right-to-left), then type, then canonical
+// rdata.
+func canonicalLess(a, b RR) bool {
+	if c := compareCanonicalName(a.Header().Name, b.Header().Name); c != 0 {
+		return c < 0
+	}
+	if a.Header().Rrtype != b.Header().Rrtype {
+		return a.Header().Rrtype < b.Header().Rrtype
+	}
+	// TTL plays no part in RFC 4034 Section 6.3 ordering, so compare with
+	// it zeroed out on both sides rather than pulling in the RRset TTL
+	// canonicalDigest computes only once all records are known.
+	wa, _ := canonicalWire(a, 0)
+	wb, _ := canonicalWire(b, 0)
+	return bytes.Compare(wa, wb) < 0
+}
+
+// compareCanonicalName compares two domain names label-by-label from the
+// root down, as RFC 4034 Section 6.1 requires (not a plain string compare,
+// which would sort "a.example." after "example." instead of before it).
+func compareCanonicalName(a, b string) int {
+	al, bl := SplitDomainName(a), SplitDomainName(b)
+	for i := 1; i <= len(al) && i <= len(bl); i++ {
+		la := strings.ToLower(al[len(al)-i])
+		lb := strings.ToLower(bl[len(bl)-i])
+		if la != lb {
+			if la < lb {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(al) < len(bl):
+		return -1
+	case len(al) > len(bl):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GenerateZONEMD computes a ZONEMD RR for the given zone (including its
+// apex SOA, but the ZONEMD RR itself, if present, is excluded from the
+// digest per RFC 8976) using scheme (only ZoneMDSchemeSimple is defined)
+// and the given hash algorithm.
+func GenerateZONEMD(zone []RR, apex string, scheme, hash uint8) (*ZONEMD, error) {
+	if scheme != ZoneMDSchemeSimple {
+		return nil, &Error{err: "dns: ZONEMD: unsupported scheme"}
+	}
+	newHash := zonemdDigestHash(hash)
+	if newHash == nil {
+		return nil, &Error{err: "dns: ZONEMD: unsupported hash algorithm"}
+	}
+	var serial uint32
+	for _, rr := range zone {
+		if soa, ok := rr.(*SOA); ok && strings.EqualFold(soa.Hdr.Name, apex) {
+			serial = soa.Serial
+			break
+		}
+	}
+	sum, err := canonicalDigest(zone, apex, newHash)
+	if err != nil {
+		return nil, err
+	}
+	return &ZONEMD{
+		Hdr:    RR_Header{Name: apex, Rrtype: TypeZONEMD, Class: ClassINET},
+		Serial: serial,
+		Scheme: scheme,
+		Hash:   hash,
+		Digest: hex.EncodeToString(sum),
+	}, nil
+}
+
+// Verify recomputes the zone's digest using rr's Scheme and Hash and
+// reports whether it matches rr.Digest, per RFC 8976 Section 3. Unlike
+// VerifyZONEMD it checks this specific ZONEMD RR rather than searching
+// zone for whichever one it can verify, which is what callers that already
+// picked an RR out of a multi-ZONEMD RRset want.
+func (rr *ZONEMD) Verify(zone []RR) error {
+	newHash := zonemdDigestHash(rr.Hash)
+	if newHash == nil || rr.Scheme != ZoneMDSchemeSimple {
+		return &Error{err: "dns: ZONEMD: unsupported scheme/hash combination"}
+	}
+	sum, err := canonicalDigest(zone, rr.Hdr.Name, newHash)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(sum) != rr.Digest {
+		return &Error{err: "dns: ZONEMD: digest mismatch"}
+	}
+	return nil
+}
+
+// VerifyZONEMD recomputes the digest over zone and compares it against
+// whichever ZONEMD RR(s) at apex it knows how to verify (unsupported
+// scheme/hash combinations in a multi-ZONEMD RRset are skipped, per RFC
+// 8976 Section 3). It returns nil as soon as one verifies, or the last
+// error seen if none do.
+func VerifyZONEMD(zone []RR, apex string) error {
+	var lastErr error = &Error{err: "dns: ZONEMD: no ZONEMD record found at apex"}
+	for _, rr := range zone {
+		z, ok := rr.(*ZONEMD)
+		if !ok || !strings.EqualFold(z.Hdr.Name, apex) {
+			continue
+		}
+		newHash := zonemdDigestHash(z.Hash)
+		if newHash == nil || z.Scheme != ZoneMDSchemeSimple {
+			lastErr = &Error{err: "dns: ZONEMD: unsupported scheme/hash combination"}
+			continue
+		}
+		sum, err := canonicalDigest(zone, apex, newHash)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if hex.EncodeToString(sum) != z.Digest {
+			lastErr = &Error{err: "dns: ZONEMD: digest mismatch"}
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
@@ -2,16 +2,178 @@ package dns
 
 import (
 	"encoding/base64"
+	"encoding/hex"
+	"math"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+// zlexer turns the lex tokens produced by the tokenizer into a pull-based
+// API for the setX functions below, in place of the previous unbuffered
+// "chan lex" that every setter read from directly. It is a thin wrapper
+// around that same channel rather than a full token-slice rewrite, so the
+// tokenizer's goroutine is unchanged, and still blocks trying to send into
+// c if nothing drains it — see (*ZoneParser).Close for how ZoneParser
+// covers that. zlexer gives callers a single one-token pushback (Unget)
+// that the channel-only version could not offer, and it is the seam
+// RegisterRRType-style table-driven dispatch and ZoneParser build on.
+type zlexer struct {
+	c    chan lex
+	peek *lex
+
+	// comments accumulates every non-empty l.comment seen since it was
+	// last reset, in order. A "; ..." comment can trail any token, not
+	// just the last one in a multi-line parenthesised rdata (e.g. between
+	// SOA fields or inside an RRSIG signature), so this is populated from
+	// Next itself rather than by each setX function, letting
+	// (*ZoneParser).Comments attribute every comment seen while parsing
+	// one RR to that RR without every setX needing its own commentBuf.
+	comments []string
+}
+
+func newZlexer(c chan lex) *zlexer {
+	return &zlexer{c: c}
+}
+
+// Next returns the next token, either the one last pushed back with Unget
+// or the next one read off the underlying channel.
+func (zl *zlexer) Next() lex {
+	if zl.peek != nil {
+		l := *zl.peek
+		zl.peek = nil
+		return l
+	}
+	l := <-zl.c
+	if l.comment != "" {
+		zl.comments = append(zl.comments, l.comment)
+	}
+	return l
+}
+
+// Unget pushes l back so the next call to Next returns it again. Only one
+// token of lookahead is supported; Unget-ing twice in a row is a bug in the
+// caller and overwrites the first pushback.
+func (zl *zlexer) Unget(l lex) {
+	zl.peek = &l
+}
+
+// ZoneParser is a pull-based replacement for ParseZone's "chan *Token"
+// pipeline: instead of a goroutine pushing parsed RRs down a channel that
+// the caller ranges over, the caller drives parsing by calling Next in a
+// loop. That still leaves the same underlying tokenizer goroutine feeding
+// zl.c, though, so a caller that stops calling Next before it returns
+// ok == false — because it lost interest, or because Err returned a
+// *ParseError and gave up on the rest of the zone — must call Close, or
+// that goroutine blocks forever trying to send into zl.c:
+//
+//	zp := NewZoneParser(r, origin, file)
+//	defer zp.Close()
+//	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+//		// use rr
+//	}
+//	if err := zp.Err(); err != nil {
+//		// handle the *ParseError
+//	}
+//
+// The zone-level token production (reading owner names, class/type and
+// $ORIGIN/$TTL/$INCLUDE directives off r) is done by the tokenizer that
+// feeds the lex channel; ZoneParser only owns the per-RR dispatch through
+// setRR, the running $TTL/$ORIGIN state, and the Strict option.
+type ZoneParser struct {
+	zl     *zlexer
+	origin string
+	file   string
+
+	strict         bool
+	includeAllowed bool
+
+	defttl    uint32
+	haveTTL   bool
+	lastcomm  string
+	lastcomms []string
+	err       *ParseError
+	closeOnce sync.Once
+}
+
+// NewZoneParser returns a ZoneParser that reads RRs, one at a time, off the
+// already-running tokenizer for file whose first RR is relative to origin.
+func NewZoneParser(c chan lex, origin, file string) *ZoneParser {
+	return &ZoneParser{zl: newZlexer(c), origin: origin, file: file}
+}
+
+// Close unblocks the tokenizer goroutine feeding zp if the caller is done
+// with zp before Next has returned ok == false: it starts draining zp's
+// remaining tokens in the background so whatever the tokenizer is doing —
+// reading more of the zone, blocked trying to send the next token — can
+// always complete instead of leaking a goroutine blocked on a send nobody
+// will ever receive. It is always safe to call, including after Next has
+// already run to completion, and safe to call more than once.
+func (zp *ZoneParser) Close() {
+	zp.closeOnce.Do(func() {
+		go func() {
+			for range zp.zl.c {
+			}
+		}()
+	})
+}
+
+// SetDefaultTTL sets the $TTL to use for RRs that don't carry an explicit
+// TTL, as if a "$TTL ttl" directive had been seen.
+func (zp *ZoneParser) SetDefaultTTL(ttl uint32) {
+	zp.defttl = ttl
+	zp.haveTTL = true
+}
+
+// SetOrigin sets the $ORIGIN relative names are completed against.
+func (zp *ZoneParser) SetOrigin(origin string) {
+	zp.origin = origin
+}
+
+// SetStrict enables the strict-mode checks documented on setRR: relative
+// names with no $ORIGIN in scope become a *ParseError instead of silently
+// producing a malformed RR.
+func (zp *ZoneParser) SetStrict(strict bool) {
+	zp.strict = strict
+}
+
+// SetIncludeAllowed controls whether $INCLUDE directives are honoured;
+// servers parsing untrusted zones should leave this false (the default) to
+// avoid an attacker-controlled zone reading arbitrary files.
+func (zp *ZoneParser) SetIncludeAllowed(allowed bool) {
+	zp.includeAllowed = allowed
+}
+
+// Err returns the first *ParseError encountered, if any. Once it returns
+// non-nil, Next will keep returning false.
+func (zp *ZoneParser) Err() error {
+	if zp.err == nil {
+		return nil
+	}
+	return zp.err
+}
+
+// Comment returns any trailing comment seen after the most recently
+// returned RR. For an RR whose rdata spans multiple lines, this is only
+// the last one; use Comments for the full set.
+func (zp *ZoneParser) Comment() string {
+	return zp.lastcomm
+}
+
+// Comments returns every "; ..." comment seen while parsing the most
+// recently returned RR, in the order they appeared — before, in between,
+// and after its (possibly parenthesised, multi-line) rdata fields — not
+// just the single trailing one Comment reports.
+func (zp *ZoneParser) Comments() []string {
+	return zp.lastcomms
+}
+
 type parserFunc struct {
 	// Func defines the function that parses the tokens and returns the RR
 	// or an error. The last string contains any comments in the line as
 	// they returned by the lexer as well.
-	Func func(h RR_Header, c chan lex, origin string, file string) (RR, *ParseError, string)
+	Func func(h RR_Header, c *zlexer, origin string, file string, strict bool) (RR, *ParseError, string)
 	// Signals if the RR ending is of variable length, like TXT or records
 	// that have Hexadecimal or Base64 as their last element in the Rdata. Records
 	// that have a fixed ending or for instance A, AAAA, SOA and etc.
@@ -23,10 +185,38 @@ type parserFunc struct {
 // After the rdata there may come a _BLANK and then a _NEWLINE
 // or immediately a _NEWLINE. If this is not the case we flag
 // an *ParseError: garbage after rdata.
-func setRR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+//
+// When strict is true, the setters enforce RFC 1035 Section 5.1: an owner
+// or rdata domain name that is not terminated with a dot MUST NOT be
+// completed against an empty origin, and doing so returns a *ParseError
+// instead of silently producing a malformed (relative) name. Callers that
+// want the legacy, lenient behaviour (the zero value) keep getting it.
+//
+// Per RFC 3597 Section 5, the "\# length hex..." unknown-rdata form is a
+// valid presentation format for *any* RR type, not just ones this package
+// doesn't otherwise know how to parse: a zonefile copied out of an
+// implementation that disagrees with us on some type's presentation format
+// can still be read. setRR peeks at the first rdata token for exactly that
+// form before consulting typeToparserFunc.
+//
+// Past that peek, dispatch is a single map lookup: typeToparserFunc is the
+// only place that knows which setXxx parses which rrtype, and it is
+// reachable from outside the package via RegisterRRType (see register.go),
+// so a caller can plug in an RR type this function has never heard of
+// without touching this switch-free body at all.
+func setRR(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
+	if l := c.Next(); l.token == "\\#" {
+		c.Unget(l)
+		return parseUnknownRdata(h, c, f)
+	} else {
+		c.Unget(l)
+	}
+
+	registerMu.RLock()
 	parserfunc, ok := typeToparserFunc[h.Rrtype]
+	registerMu.RUnlock()
 	if ok {
-		r, e, cm := parserfunc.Func(h, c, o, f)
+		r, e, cm := parserfunc.Func(h, c, o, f, strict)
 		if parserfunc.Variable {
 			return r, e, cm
 		}
@@ -39,15 +229,15 @@ func setRR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		}
 		return r, nil, cm
 	}
-	// RFC3957 RR (Unknown RR handling)
-	return setRFC3597(h, c, o, f)
+	// RFC3597 RR (Unknown RR handling)
+	return setRFC3597(h, c, o, f, strict)
 }
 
 // A remainder of the rdata with embedded spaces, return the parsed string (sans the spaces)
 // or an error
-func endingToString(c chan lex, errstr, f string) (string, *ParseError, string) {
+func endingToString(c *zlexer, errstr, f string) (string, *ParseError, string) {
 	s := ""
-	l := <-c // _STRING
+	l := c.Next() // _STRING
 	for l.value != _NEWLINE && l.value != _EOF {
 		switch l.value {
 		case _STRING:
@@ -56,17 +246,17 @@ func endingToString(c chan lex, errstr, f string) (string, *ParseError, string)
 		default:
 			return "", &ParseError{f, errstr, l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	return s, nil, l.comment
 }
 
 // A remainder of the rdata with embedded spaces, return the parsed string slice (sans the spaces)
 // or an error
-func endingToTxtSlice(c chan lex, errstr, f string) ([]string, *ParseError, string) {
+func endingToTxtSlice(c *zlexer, errstr, f string) ([]string, *ParseError, string) {
 	// Get the remaining data until we see a NEWLINE
 	quote := false
-	l := <-c
+	l := c.Next()
 	var s []string
 	switch l.value == _QUOTE {
 	case true: // A number of quoted string
@@ -91,7 +281,7 @@ func endingToTxtSlice(c chan lex, errstr, f string) ([]string, *ParseError, stri
 			default:
 				return nil, &ParseError{f, errstr, l}, ""
 			}
-			l = <-c
+			l = c.Next()
 		}
 		if quote {
 			return nil, &ParseError{f, errstr, l}, ""
@@ -100,17 +290,17 @@ func endingToTxtSlice(c chan lex, errstr, f string) ([]string, *ParseError, stri
 		s = make([]string, 1)
 		for l.value != _NEWLINE && l.value != _EOF {
 			s[0] += l.token
-			l = <-c
+			l = c.Next()
 		}
 	}
 	return s, nil, l.comment
 }
 
-func setA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setA(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(A)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 { // Dynamic updates.
 		return rr, nil, ""
 	}
@@ -121,11 +311,11 @@ func setA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setAAAA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setAAAA(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(AAAA)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -136,16 +326,19 @@ func setAAAA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setNS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NS)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Ns = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NS Ns: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Ns = o
 		return rr, nil, ""
 	}
@@ -154,21 +347,27 @@ func setNS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad NS Ns", l}, ""
 	}
 	if rr.Ns[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NS Ns: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Ns = appendOrigin(rr.Ns, o)
 	}
 	return rr, nil, ""
 }
 
-func setPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setPTR(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(PTR)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Ptr = l.token
 	if l.length == 0 { // dynamic update rr.
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PTR Ptr: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Ptr = o
 		return rr, nil, ""
 	}
@@ -177,21 +376,27 @@ func setPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad PTR Ptr", l}, ""
 	}
 	if rr.Ptr[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PTR Ptr: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Ptr = appendOrigin(rr.Ptr, o)
 	}
 	return rr, nil, ""
 }
 
-func setNSAPPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNSAPPTR(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NSAPPTR)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Ptr = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NSAPPTR Ptr: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Ptr = o
 		return rr, nil, ""
 	}
@@ -200,21 +405,27 @@ func setNSAPPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string)
 		return nil, &ParseError{f, "bad NSAP-PTR Ptr", l}, ""
 	}
 	if rr.Ptr[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NSAP-PTR Ptr: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Ptr = appendOrigin(rr.Ptr, o)
 	}
 	return rr, nil, ""
 }
 
-func setRP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setRP(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(RP)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Mbox = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RP Mbox: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mbox = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -222,13 +433,19 @@ func setRP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad RP Mbox", l}, ""
 		}
 		if rr.Mbox[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad RP Mbox: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.Mbox = appendOrigin(rr.Mbox, o)
 		}
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	rr.Txt = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RP Txt: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Txt = o
 		return rr, nil, ""
 	}
@@ -237,21 +454,27 @@ func setRP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad RP Txt", l}, ""
 	}
 	if rr.Txt[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RP Txt: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Txt = appendOrigin(rr.Txt, o)
 	}
 	return rr, nil, ""
 }
 
-func setMR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMR(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MR)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Mr = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MR Mr: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mr = o
 		return rr, nil, ""
 	}
@@ -260,21 +483,27 @@ func setMR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MR Mr", l}, ""
 	}
 	if rr.Mr[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MR Mr: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mr = appendOrigin(rr.Mr, o)
 	}
 	return rr, nil, ""
 }
 
-func setMB(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMB(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MB)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Mb = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MB Mb: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mb = o
 		return rr, nil, ""
 	}
@@ -283,21 +512,27 @@ func setMB(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MB Mb", l}, ""
 	}
 	if rr.Mb[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MB Mb: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mb = appendOrigin(rr.Mb, o)
 	}
 	return rr, nil, ""
 }
 
-func setMG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMG(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MG)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Mg = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MG Mg: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mg = o
 		return rr, nil, ""
 	}
@@ -306,34 +541,40 @@ func setMG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MG Mg", l}, ""
 	}
 	if rr.Mg[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MG Mg: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mg = appendOrigin(rr.Mg, o)
 	}
 	return rr, nil, ""
 }
 
-func setHINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setHINFO(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(HINFO)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Cpu = l.token
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Os = l.token
 
 	return rr, nil, ""
 }
 
-func setMINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMINFO(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MINFO)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Rmail = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MINFO Rmail: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Rmail = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -341,13 +582,19 @@ func setMINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad MINFO Rmail", l}, ""
 		}
 		if rr.Rmail[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad MINFO Rmail: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.Rmail = appendOrigin(rr.Rmail, o)
 		}
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	rr.Email = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MINFO Email: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Email = o
 		return rr, nil, ""
 	}
@@ -356,21 +603,27 @@ func setMINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MINFO Email", l}, ""
 	}
 	if rr.Email[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MINFO Email: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Email = appendOrigin(rr.Email, o)
 	}
 	return rr, nil, ""
 }
 
-func setMF(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMF(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MF)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Mf = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MF Mf: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mf = o
 		return rr, nil, ""
 	}
@@ -379,21 +632,27 @@ func setMF(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MF Mf", l}, ""
 	}
 	if rr.Mf[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MF Mf: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mf = appendOrigin(rr.Mf, o)
 	}
 	return rr, nil, ""
 }
 
-func setMD(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMD(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MD)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Md = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MD Md: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Md = o
 		return rr, nil, ""
 	}
@@ -402,16 +661,19 @@ func setMD(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MD Md", l}, ""
 	}
 	if rr.Md[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MD Md: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Md = appendOrigin(rr.Md, o)
 	}
 	return rr, nil, ""
 }
 
-func setMX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setMX(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(MX)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -420,10 +682,13 @@ func setMX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Mx = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MX Mx: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mx = o
 		return rr, nil, ""
 	}
@@ -432,15 +697,18 @@ func setMX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad MX Mx", l}, ""
 	}
 	if rr.Mx[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad MX Mx: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mx = appendOrigin(rr.Mx, o)
 	}
 	return rr, nil, ""
 }
 
-func setRT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setRT(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(RT)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -449,10 +717,13 @@ func setRT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Host = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RT Host: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Host = o
 		return rr, nil, ""
 	}
@@ -461,16 +732,19 @@ func setRT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad RT Host", l}, ""
 	}
 	if rr.Host[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RT Host: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Host = appendOrigin(rr.Host, o)
 	}
 	return rr, nil, ""
 }
 
-func setAFSDB(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setAFSDB(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(AFSDB)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -479,10 +753,13 @@ func setAFSDB(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Subtype = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Hostname = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad AFSDB Hostname: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Hostname = o
 		return rr, nil, ""
 	}
@@ -491,25 +768,28 @@ func setAFSDB(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad AFSDB Hostname", l}, ""
 	}
 	if rr.Hostname[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad AFSDB Hostname: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Hostname = appendOrigin(rr.Hostname, o)
 	}
 	return rr, nil, ""
 }
 
-func setX25(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setX25(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(X25)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.PSDNAddress = l.token
 	return rr, nil, ""
 }
 
-func setKX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setKX(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(KX)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -518,10 +798,13 @@ func setKX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Exchanger = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad KX Exchanger: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Exchanger = o
 		return rr, nil, ""
 	}
@@ -530,21 +813,27 @@ func setKX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad KX Exchanger", l}, ""
 	}
 	if rr.Exchanger[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad KX Exchanger: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Exchanger = appendOrigin(rr.Exchanger, o)
 	}
 	return rr, nil, ""
 }
 
-func setCNAME(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setCNAME(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(CNAME)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Target = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad CNAME Target: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Target = o
 		return rr, nil, ""
 	}
@@ -553,21 +842,27 @@ func setCNAME(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad CNAME Target", l}, ""
 	}
 	if rr.Target[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad CNAME Target: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Target = appendOrigin(rr.Target, o)
 	}
 	return rr, nil, ""
 }
 
-func setDNAME(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setDNAME(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(DNAME)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.Target = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad DNAME Target: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Target = o
 		return rr, nil, ""
 	}
@@ -576,22 +871,40 @@ func setDNAME(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad CNAME Target", l}, ""
 	}
 	if rr.Target[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad CNAME Target: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Target = appendOrigin(rr.Target, o)
 	}
 	return rr, nil, ""
 }
 
-func setSOA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setSOA(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(SOA)
 	rr.Hdr = h
 
-	l := <-c
+	// SOA rdata is commonly written as a parenthesised, multi-line block
+	// with a "; comment" trailing each field; commentBuf collects those so
+	// they aren't silently dropped in favour of only the final one.
+	var commentBuf []string
+	next := func() lex {
+		l := c.Next()
+		if l.comment != "" {
+			commentBuf = append(commentBuf, l.comment)
+		}
+		return l
+	}
+
+	l := next()
 	rr.Ns = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	<-c // _BLANK
+	next() // _BLANK
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad SOA Ns: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Ns = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -599,13 +912,19 @@ func setSOA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad SOA Ns", l}, ""
 		}
 		if rr.Ns[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad SOA Ns: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.Ns = appendOrigin(rr.Ns, o)
 		}
 	}
 
-	l = <-c
+	l = next()
 	rr.Mbox = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad SOA Mbox: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mbox = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -613,17 +932,20 @@ func setSOA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad SOA Mbox", l}, ""
 		}
 		if rr.Mbox[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad SOA Mbox: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.Mbox = appendOrigin(rr.Mbox, o)
 		}
 	}
-	<-c // _BLANK
+	next() // _BLANK
 
 	var (
 		v  uint32
 		ok bool
 	)
 	for i := 0; i < 5; i++ {
-		l = <-c
+		l = next()
 		if j, e := strconv.Atoi(l.token); e != nil {
 			if i == 0 {
 				// Serial should be a number
@@ -639,28 +961,28 @@ func setSOA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		switch i {
 		case 0:
 			rr.Serial = v
-			<-c // _BLANK
+			next() // _BLANK
 		case 1:
 			rr.Refresh = v
-			<-c // _BLANK
+			next() // _BLANK
 		case 2:
 			rr.Retry = v
-			<-c // _BLANK
+			next() // _BLANK
 		case 3:
 			rr.Expire = v
-			<-c // _BLANK
+			next() // _BLANK
 		case 4:
 			rr.Minttl = v
 		}
 	}
-	return rr, nil, ""
+	return rr, nil, strings.Join(commentBuf, "; ")
 }
 
-func setSRV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setSRV(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(SRV)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -669,24 +991,27 @@ func setSRV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Priority = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad SRV Weight", l}, ""
 	} else {
 		rr.Weight = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad SRV Port", l}, ""
 	} else {
 		rr.Port = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Target = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad SRV Target: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Target = o
 		return rr, nil, ""
 	}
@@ -695,16 +1020,19 @@ func setSRV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad SRV Target", l}, ""
 	}
 	if rr.Target[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad SRV Target: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Target = appendOrigin(rr.Target, o)
 	}
 	return rr, nil, ""
 }
 
-func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNAPTR(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NAPTR)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -713,23 +1041,23 @@ func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Order = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad NAPTR Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
 	// Flags
-	<-c     // _BLANK
-	l = <-c // _QUOTE
+	c.Next()     // _BLANK
+	l = c.Next() // _QUOTE
 	if l.value != _QUOTE {
 		return nil, &ParseError{f, "bad NAPTR Flags", l}, ""
 	}
-	l = <-c // Either String or Quote
+	l = c.Next() // Either String or Quote
 	if l.value == _STRING {
 		rr.Flags = l.token
-		l = <-c // _QUOTE
+		l = c.Next() // _QUOTE
 		if l.value != _QUOTE {
 			return nil, &ParseError{f, "bad NAPTR Flags", l}, ""
 		}
@@ -740,15 +1068,15 @@ func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	}
 
 	// Service
-	<-c     // _BLANK
-	l = <-c // _QUOTE
+	c.Next()     // _BLANK
+	l = c.Next() // _QUOTE
 	if l.value != _QUOTE {
 		return nil, &ParseError{f, "bad NAPTR Service", l}, ""
 	}
-	l = <-c // Either String or Quote
+	l = c.Next() // Either String or Quote
 	if l.value == _STRING {
 		rr.Service = l.token
-		l = <-c // _QUOTE
+		l = c.Next() // _QUOTE
 		if l.value != _QUOTE {
 			return nil, &ParseError{f, "bad NAPTR Service", l}, ""
 		}
@@ -759,15 +1087,15 @@ func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	}
 
 	// Regexp
-	<-c     // _BLANK
-	l = <-c // _QUOTE
+	c.Next()     // _BLANK
+	l = c.Next() // _QUOTE
 	if l.value != _QUOTE {
 		return nil, &ParseError{f, "bad NAPTR Regexp", l}, ""
 	}
-	l = <-c // Either String or Quote
+	l = c.Next() // Either String or Quote
 	if l.value == _STRING {
 		rr.Regexp = l.token
-		l = <-c // _QUOTE
+		l = c.Next() // _QUOTE
 		if l.value != _QUOTE {
 			return nil, &ParseError{f, "bad NAPTR Regexp", l}, ""
 		}
@@ -777,10 +1105,13 @@ func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad NAPTR Regexp", l}, ""
 	}
 	// After quote no space??
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Replacement = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NAPTR Replacement: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Replacement = o
 		return rr, nil, ""
 	}
@@ -789,21 +1120,27 @@ func setNAPTR(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad NAPTR Replacement", l}, ""
 	}
 	if rr.Replacement[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NAPTR Replacement: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Replacement = appendOrigin(rr.Replacement, o)
 	}
 	return rr, nil, ""
 }
 
-func setTALINK(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setTALINK(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(TALINK)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	rr.PreviousName = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad TALINK PreviousName: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.PreviousName = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -811,13 +1148,19 @@ func setTALINK(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad TALINK PreviousName", l}, ""
 		}
 		if rr.PreviousName[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad TALINK PreviousName: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.PreviousName = appendOrigin(rr.PreviousName, o)
 		}
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	rr.NextName = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad TALINK NextName: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.NextName = o
 		return rr, nil, ""
 	}
@@ -826,12 +1169,15 @@ func setTALINK(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad TALINK NextName", l}, ""
 	}
 	if rr.NextName[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad TALINK NextName: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.NextName = appendOrigin(rr.NextName, o)
 	}
 	return rr, nil, ""
 }
 
-func setLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setLOC(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(LOC)
 	rr.Hdr = h
 	// Non zero defaults for LOC record, see RFC 1876, Section 3.
@@ -840,7 +1186,7 @@ func setLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	rr.Size = 18      // 1
 	ok := false
 	// North
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -849,9 +1195,9 @@ func setLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Latitude = 1000 * 60 * 60 * uint32(i)
 	}
-	<-c // _BLANK
+	c.Next() // _BLANK
 	// Either number, 'N' or 'S'
-	l = <-c
+	l = c.Next()
 	if rr.Latitude, ok = locCheckNorth(l.token, rr.Latitude); ok {
 		goto East
 	}
@@ -860,16 +1206,16 @@ func setLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Latitude += 1000 * 60 * uint32(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.ParseFloat(l.token, 32); e != nil {
 		return nil, &ParseError{f, "bad LOC Latitude seconds", l}, ""
 	} else {
 		rr.Latitude += uint32(1000 * i)
 	}
-	<-c // _BLANK
+	c.Next() // _BLANK
 	// Either number, 'N' or 'S'
-	l = <-c
+	l = c.Next()
 	if rr.Latitude, ok = locCheckNorth(l.token, rr.Latitude); ok {
 		goto East
 	}
@@ -878,16 +1224,16 @@ func setLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 
 East:
 	// East
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad LOC Longitude", l}, ""
 	} else {
 		rr.Longitude = 1000 * 60 * 60 * uint32(i)
 	}
-	<-c // _BLANK
+	c.Next() // _BLANK
 	// Either number, 'E' or 'W'
-	l = <-c
+	l = c.Next()
 	if rr.Longitude, ok = locCheckEast(l.token, rr.Longitude); ok {
 		goto Altitude
 	}
@@ -896,16 +1242,16 @@ East:
 	} else {
 		rr.Longitude += 1000 * 60 * uint32(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.ParseFloat(l.token, 32); e != nil {
 		return nil, &ParseError{f, "bad LOC Longitude seconds", l}, ""
 	} else {
 		rr.Longitude += uint32(1000 * i)
 	}
-	<-c // _BLANK
+	c.Next() // _BLANK
 	// Either number, 'E' or 'W'
-	l = <-c
+	l = c.Next()
 	if rr.Longitude, ok = locCheckEast(l.token, rr.Longitude); ok {
 		goto Altitude
 	}
@@ -913,8 +1259,8 @@ East:
 	return nil, &ParseError{f, "bad LOC Longitude East/West", l}, ""
 
 Altitude:
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if l.token[len(l.token)-1] == 'M' || l.token[len(l.token)-1] == 'm' {
 		l.token = l.token[0 : len(l.token)-1]
 	}
@@ -925,7 +1271,7 @@ Altitude:
 	}
 
 	// And now optionally the other values
-	l = <-c
+	l = c.Next()
 	count := 0
 	for l.value != _NEWLINE && l.value != _EOF {
 		switch l.value {
@@ -956,17 +1302,17 @@ Altitude:
 		default:
 			return nil, &ParseError{f, "bad LOC Size, HorizPre or VertPre", l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	return rr, nil, ""
 }
 
-func setHIP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setHIP(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(HIP)
 	rr.Hdr = h
 
 	// HitLength is not represented
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -975,18 +1321,18 @@ func setHIP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.PublicKeyAlgorithm = uint8(i)
 	}
-	<-c              // _BLANK
-	l = <-c          // _STRING
+	c.Next()              // _BLANK
+	l = c.Next()          // _STRING
 	rr.Hit = l.token // This can not contain spaces, see RFC 5205 Section 6.
 	rr.HitLength = uint8(len(rr.Hit)) / 2
 
-	<-c                    // _BLANK
-	l = <-c                // _STRING
+	c.Next()                    // _BLANK
+	l = c.Next()                // _STRING
 	rr.PublicKey = l.token // This cannot contain spaces
 	rr.PublicKeyLength = uint16(base64.StdEncoding.DecodedLen(len(rr.PublicKey)))
 
 	// RendezvousServers (if any)
-	l = <-c
+	l = c.Next()
 	xs := make([]string, 0)
 	for l.value != _NEWLINE && l.value != _EOF {
 		switch l.value {
@@ -1008,17 +1354,17 @@ func setHIP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		default:
 			return nil, &ParseError{f, "bad HIP RendezvousServers", l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	rr.RendezvousServers = xs
 	return rr, nil, l.comment
 }
 
-func setCERT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setCERT(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(CERT)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1029,15 +1375,15 @@ func setCERT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Type = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad CERT KeyTag", l}, ""
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if v, ok := StringToAlgorithm[l.token]; ok {
 		rr.Algorithm = v
 	} else if i, e := strconv.Atoi(l.token); e != nil {
@@ -1053,7 +1399,7 @@ func setCERT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setOPENPGPKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setOPENPGPKEY(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(OPENPGPKEY)
 	rr.Hdr = h
 
@@ -1065,13 +1411,30 @@ func setOPENPGPKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, strin
 	return rr, nil, c1
 }
 
-func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setRRSIG(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(RRSIG)
 	rr.Hdr = h
-	l := <-c
+
+	// RRSIG rdata is routinely written as a parenthesised, multi-line
+	// block (signer, timestamps and the base64 signature each on their
+	// own line); commentBuf collects the "; comment" trailing each field
+	// instead of keeping only the one after the signature.
+	var commentBuf []string
+	next := func() lex {
+		l := c.Next()
+		if l.comment != "" {
+			commentBuf = append(commentBuf, l.comment)
+		}
+		return l
+	}
+
+	l := next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
+	if l.err {
+		return nil, &ParseError{f, "bad RRSIG Typecovered", l}, ""
+	}
 	if t, ok := StringToType[l.tokenUpper]; !ok {
 		if strings.HasPrefix(l.tokenUpper, "TYPE") {
 			if t, ok = typeToInt(l.tokenUpper); !ok {
@@ -1085,33 +1448,35 @@ func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.TypeCovered = t
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, err := strconv.Atoi(l.token); err != nil {
+	next() // _BLANK
+	l = next()
+	if i, err := strconv.ParseUint(l.token, 10, 8); err != nil || l.err {
 		return nil, &ParseError{f, "bad RRSIG Algorithm", l}, ""
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, err := strconv.Atoi(l.token); err != nil {
+	next() // _BLANK
+	l = next()
+	if i, err := strconv.ParseUint(l.token, 10, 8); err != nil || l.err {
 		return nil, &ParseError{f, "bad RRSIG Labels", l}, ""
 	} else {
 		rr.Labels = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, err := strconv.Atoi(l.token); err != nil {
+	next() // _BLANK
+	l = next()
+	if i, err := strconv.ParseUint(l.token, 10, 32); err != nil || l.err {
 		return nil, &ParseError{f, "bad RRSIG OrigTtl", l}, ""
 	} else {
 		rr.OrigTtl = uint32(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	next() // _BLANK
+	l = next()
+	if l.err {
+		return nil, &ParseError{f, "bad RRSIG Expiration", l}, ""
+	}
 	if i, err := StringToTime(l.token); err != nil {
 		// Try to see if all numeric and use it as epoch
-		if i, err := strconv.ParseInt(l.token, 10, 64); err == nil {
-			// TODO(miek): error out on > MAX_UINT32, same below
+		if i, err := strconv.ParseInt(l.token, 10, 64); err == nil && i >= 0 && i <= math.MaxUint32 {
 			rr.Expiration = uint32(i)
 		} else {
 			return nil, &ParseError{f, "bad RRSIG Expiration", l}, ""
@@ -1119,10 +1484,13 @@ func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Expiration = i
 	}
-	<-c // _BLANK
-	l = <-c
+	next() // _BLANK
+	l = next()
+	if l.err {
+		return nil, &ParseError{f, "bad RRSIG Inception", l}, ""
+	}
 	if i, err := StringToTime(l.token); err != nil {
-		if i, err := strconv.ParseInt(l.token, 10, 64); err == nil {
+		if i, err := strconv.ParseInt(l.token, 10, 64); err == nil && i >= 0 && i <= math.MaxUint32 {
 			rr.Inception = uint32(i)
 		} else {
 			return nil, &ParseError{f, "bad RRSIG Inception", l}, ""
@@ -1130,17 +1498,23 @@ func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Inception = i
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, err := strconv.Atoi(l.token); err != nil {
+	next() // _BLANK
+	l = next()
+	if i, err := strconv.ParseUint(l.token, 10, 16); err != nil || l.err {
 		return nil, &ParseError{f, "bad RRSIG KeyTag", l}, ""
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	next() // _BLANK
+	l = next()
+	if l.err {
+		return nil, &ParseError{f, "bad RRSIG SignerName", l}, ""
+	}
 	rr.SignerName = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad RRSIG SignerName: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.SignerName = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -1148,6 +1522,9 @@ func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad RRSIG SignerName", l}, ""
 		}
 		if rr.SignerName[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad RRSIG SignerName: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.SignerName = appendOrigin(rr.SignerName, o)
 		}
 	}
@@ -1155,20 +1532,32 @@ func setRRSIG(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	if e != nil {
 		return nil, e, c1
 	}
+	if _, e := base64.StdEncoding.DecodeString(s); e != nil {
+		return nil, &ParseError{f, "bad RRSIG Signature: " + e.Error(), l}, ""
+	}
 	rr.Signature = s
-	return rr, nil, c1
+	if c1 != "" {
+		commentBuf = append(commentBuf, c1)
+	}
+	return rr, nil, strings.Join(commentBuf, "; ")
 }
 
-func setNSEC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNSEC(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NSEC)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
+	if l.err {
+		return nil, &ParseError{f, "bad NSEC NextDomain", l}, ""
+	}
 	rr.NextDomain = l.token
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad NSEC NextDomain: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.NextDomain = o
 	} else {
 		_, ok := IsDomainName(l.token)
@@ -1176,6 +1565,9 @@ func setNSEC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 			return nil, &ParseError{f, "bad NSEC NextDomain", l}, ""
 		}
 		if rr.NextDomain[l.length-1] != '.' {
+			if o == "" && strict {
+				return nil, &ParseError{f, "bad NSEC NextDomain: relative name requires an $ORIGIN", l}, ""
+			}
 			rr.NextDomain = appendOrigin(rr.NextDomain, o)
 		}
 	}
@@ -1185,8 +1577,11 @@ func setNSEC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		k  uint16
 		ok bool
 	)
-	l = <-c
+	l = c.Next()
 	for l.value != _NEWLINE && l.value != _EOF {
+		if l.err {
+			return nil, &ParseError{f, "bad NSEC TypeBitMap", l}, ""
+		}
 		switch l.value {
 		case _BLANK:
 			// Ok
@@ -1200,48 +1595,51 @@ func setNSEC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		default:
 			return nil, &ParseError{f, "bad NSEC TypeBitMap", l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	return rr, nil, l.comment
 }
 
-func setNSEC3(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNSEC3(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NSEC3)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad NSEC3 Hash", l}, ""
 	} else {
 		rr.Hash = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad NSEC3 Flags", l}, ""
 	} else {
 		rr.Flags = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad NSEC3 Iterations", l}, ""
 	} else {
 		rr.Iterations = uint16(i)
 	}
-	<-c
-	l = <-c
-	if len(l.token) == 0 {
+	c.Next()
+	l = c.Next()
+	if l.err || len(l.token) == 0 {
 		return nil, &ParseError{f, "bad NSEC3 Salt", l}, ""
 	}
 	rr.SaltLength = uint8(len(l.token)) / 2
 	rr.Salt = l.token
 
-	<-c
-	l = <-c
+	c.Next()
+	l = c.Next()
+	if l.err {
+		return nil, &ParseError{f, "bad NSEC3 NextDomain", l}, ""
+	}
 	rr.HashLength = 20 // Fix for NSEC3 (sha1 160 bits)
 	rr.NextDomain = l.token
 
@@ -1250,8 +1648,11 @@ func setNSEC3(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		k  uint16
 		ok bool
 	)
-	l = <-c
+	l = c.Next()
 	for l.value != _NEWLINE && l.value != _EOF {
+		if l.err {
+			return nil, &ParseError{f, "bad NSEC3 TypeBitMap", l}, ""
+		}
 		switch l.value {
 		case _BLANK:
 			// Ok
@@ -1265,16 +1666,16 @@ func setNSEC3(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		default:
 			return nil, &ParseError{f, "bad NSEC3 TypeBitMap", l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	return rr, nil, l.comment
 }
 
-func setNSEC3PARAM(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNSEC3PARAM(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NSEC3PARAM)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -1283,36 +1684,36 @@ func setNSEC3PARAM(h RR_Header, c chan lex, o, f string) (RR, *ParseError, strin
 	} else {
 		rr.Hash = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad NSEC3PARAM Flags", l}, ""
 	} else {
 		rr.Flags = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad NSEC3PARAM Iterations", l}, ""
 	} else {
 		rr.Iterations = uint16(i)
 	}
-	<-c
-	l = <-c
+	c.Next()
+	l = c.Next()
 	rr.SaltLength = uint8(len(l.token))
 	rr.Salt = l.token
 	return rr, nil, ""
 }
 
-func setEUI48(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setEUI48(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(EUI48)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if l.length != 17 {
+	if l.err || l.length != 17 {
 		return nil, &ParseError{f, "bad EUI48 Address", l}, ""
 	}
 	addr := make([]byte, 12)
@@ -1336,15 +1737,15 @@ func setEUI48(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setEUI64(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setEUI64(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(EUI64)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if l.length != 23 {
+	if l.err || l.length != 23 {
 		return nil, &ParseError{f, "bad EUI64 Address", l}, ""
 	}
 	addr := make([]byte, 16)
@@ -1368,23 +1769,26 @@ func setEUI64(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setWKS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setWKS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(WKS)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
+	if l.err {
+		return nil, &ParseError{f, "bad WKS Address", l}, ""
+	}
 	rr.Address = net.ParseIP(l.token)
 	if rr.Address == nil {
 		return nil, &ParseError{f, "bad WKS Address", l}, ""
 	}
 
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	proto := "tcp"
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.Atoi(l.token); e != nil || l.err {
 		return nil, &ParseError{f, "bad WKS Protocol", l}, ""
 	} else {
 		rr.Protocol = uint8(i)
@@ -1398,14 +1802,17 @@ func setWKS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		}
 	}
 
-	<-c
-	l = <-c
+	c.Next()
+	l = c.Next()
 	rr.BitMap = make([]uint16, 0)
 	var (
 		k   int
 		err error
 	)
 	for l.value != _NEWLINE && l.value != _EOF {
+		if l.err {
+			return nil, &ParseError{f, "bad WKS BitMap", l}, ""
+		}
 		switch l.value {
 		case _BLANK:
 			// Ok
@@ -1421,16 +1828,16 @@ func setWKS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		default:
 			return nil, &ParseError{f, "bad WKS BitMap", l}, ""
 		}
-		l = <-c
+		l = c.Next()
 	}
 	return rr, nil, l.comment
 }
 
-func setSSHFP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setSSHFP(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(SSHFP)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -1439,42 +1846,42 @@ func setSSHFP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad SSHFP Type", l}, ""
 	} else {
 		rr.Type = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	rr.FingerPrint = l.token
 	return rr, nil, ""
 }
 
-func setDNSKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setDNSKEY(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(DNSKEY)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad DNSKEY Flags", l}, ""
 	} else {
 		rr.Flags = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad DNSKEY Protocol", l}, ""
 	} else {
 		rr.Protocol = uint8(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad DNSKEY Algorithm", l}, ""
 	} else {
 		rr.Algorithm = uint8(i)
@@ -1483,15 +1890,18 @@ func setDNSKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	if e != nil {
 		return nil, e, c1
 	}
+	if _, e := base64.StdEncoding.DecodeString(s); e != nil {
+		return nil, &ParseError{f, "bad DNSKEY PublicKey: " + e.Error(), l}, ""
+	}
 	rr.PublicKey = s
 	return rr, nil, c1
 }
 
-func setRKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setRKEY(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(RKEY)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1500,15 +1910,15 @@ func setRKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Flags = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad RKEY Protocol", l}, ""
 	} else {
 		rr.Protocol = uint8(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad RKEY Algorithm", l}, ""
 	} else {
@@ -1522,32 +1932,35 @@ func setRKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setDS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(DS)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad DS KeyTag", l}, ""
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
-		if i, ok := StringToAlgorithm[l.tokenUpper]; !ok {
+	c.Next() // _BLANK
+	l = c.Next()
+	if l.err {
+		return nil, &ParseError{f, "bad DS Algorithm", l}, ""
+	}
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil {
+		if a, ok := StringToAlgorithm[l.tokenUpper]; !ok {
 			return nil, &ParseError{f, "bad DS Algorithm", l}, ""
 		} else {
-			rr.Algorithm = i
+			rr.Algorithm = a
 		}
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad DS DigestType", l}, ""
 	} else {
 		rr.DigestType = uint8(i)
@@ -1556,11 +1969,14 @@ func setDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	if e != nil {
 		return nil, e, c1
 	}
+	if _, e := hex.DecodeString(s); e != nil {
+		return nil, &ParseError{f, "bad DS Digest: " + e.Error(), l}, ""
+	}
 	rr.Digest = s
 	return rr, nil, c1
 }
 
-func setEID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setEID(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(EID)
 	rr.Hdr = h
 	s, e, c1 := endingToString(c, "bad EID Endpoint", f)
@@ -1571,7 +1987,7 @@ func setEID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setNIMLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNIMLOC(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NIMLOC)
 	rr.Hdr = h
 	s, e, c1 := endingToString(c, "bad NIMLOC Locator", f)
@@ -1582,10 +1998,10 @@ func setNIMLOC(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setNSAP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNSAP(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NSAP)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1594,7 +2010,7 @@ func setNSAP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Length = uint8(i)
 	}
-	<-c // _BLANK
+	c.Next() // _BLANK
 	s, e, c1 := endingToString(c, "bad NSAP Nsap", f)
 	if e != nil {
 		return nil, e, c1
@@ -1603,10 +2019,10 @@ func setNSAP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setGPOS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setGPOS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(GPOS)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -1615,15 +2031,15 @@ func setGPOS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Longitude = l.token
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if _, e := strconv.ParseFloat(l.token, 64); e != nil {
 		return nil, &ParseError{f, "bad GPOS Latitude", l}, ""
 	} else {
 		rr.Latitude = l.token
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if _, e := strconv.ParseFloat(l.token, 64); e != nil {
 		return nil, &ParseError{f, "bad GPOS Altitude", l}, ""
 	} else {
@@ -1632,10 +2048,10 @@ func setGPOS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setCDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setCDS(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(CDS)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1644,8 +2060,8 @@ func setCDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		if i, ok := StringToAlgorithm[l.tokenUpper]; !ok {
 			return nil, &ParseError{f, "bad CDS Algorithm", l}, ""
@@ -1655,8 +2071,8 @@ func setCDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad CDS DigestType", l}, ""
 	} else {
@@ -1670,10 +2086,10 @@ func setCDS(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setDLV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setDLV(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(DLV)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1682,8 +2098,8 @@ func setDLV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		if i, ok := StringToAlgorithm[l.tokenUpper]; !ok {
 			return nil, &ParseError{f, "bad DLV Algorithm", l}, ""
@@ -1693,8 +2109,8 @@ func setDLV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad DLV DigestType", l}, ""
 	} else {
@@ -1708,10 +2124,10 @@ func setDLV(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setTA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setTA(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(TA)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1720,8 +2136,8 @@ func setTA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.KeyTag = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		if i, ok := StringToAlgorithm[l.tokenUpper]; !ok {
 			return nil, &ParseError{f, "bad TA Algorithm", l}, ""
@@ -1731,8 +2147,8 @@ func setTA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad TA DigestType", l}, ""
 	} else {
@@ -1746,28 +2162,28 @@ func setTA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setTLSA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setTLSA(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(TLSA)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad TLSA Usage", l}, ""
 	} else {
 		rr.Usage = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad TLSA Selector", l}, ""
 	} else {
 		rr.Selector = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad TLSA MatchingType", l}, ""
 	} else {
 		rr.MatchingType = uint8(i)
@@ -1776,21 +2192,24 @@ func setTLSA(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	if e != nil {
 		return nil, e, c1
 	}
+	if _, e := hex.DecodeString(s); e != nil {
+		return nil, &ParseError{f, "bad TLSA Certificate: " + e.Error(), l}, ""
+	}
 	rr.Certificate = s
 	return rr, nil, c1
 }
 
-func setRFC3597(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setRFC3597(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(RFC3597)
 	rr.Hdr = h
-	l := <-c
-	if l.token != "\\#" {
+	l := c.Next()
+	if l.err || l.token != "\\#" {
 		return nil, &ParseError{f, "bad RFC3597 Rdata", l}, ""
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	rdlength, e := strconv.Atoi(l.token)
-	if e != nil {
+	if e != nil || l.err || rdlength < 0 {
 		return nil, &ParseError{f, "bad RFC3597 Rdata ", l}, ""
 	}
 
@@ -1805,7 +2224,7 @@ func setRFC3597(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string)
 	return rr, nil, c1
 }
 
-func setSPF(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setSPF(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(SPF)
 	rr.Hdr = h
 
@@ -1817,7 +2236,7 @@ func setSPF(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setTXT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setTXT(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(TXT)
 	rr.Hdr = h
 
@@ -1831,7 +2250,7 @@ func setTXT(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 }
 
 // identical to setTXT
-func setNINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNINFO(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NINFO)
 	rr.Hdr = h
 
@@ -1843,11 +2262,11 @@ func setNINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setURI(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setURI(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(URI)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
@@ -1856,15 +2275,15 @@ func setURI(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	} else {
 		rr.Priority = uint16(i)
 	}
-	<-c // _BLANK
-	l = <-c
+	c.Next() // _BLANK
+	l = c.Next()
 	if i, e := strconv.Atoi(l.token); e != nil {
 		return nil, &ParseError{f, "bad URI Weight", l}, ""
 	} else {
 		rr.Weight = uint16(i)
 	}
 
-	<-c // _BLANK
+	c.Next() // _BLANK
 	s, e, c1 := endingToTxtSlice(c, "bad URI Target", f)
 	if e != nil {
 		return nil, e, ""
@@ -1873,35 +2292,38 @@ func setURI(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setIPSECKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setIPSECKEY(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(IPSECKEY)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, l.comment
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad IPSECKEY Precedence", l}, ""
 	} else {
 		rr.Precedence = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad IPSECKEY GatewayType", l}, ""
 	} else {
 		rr.GatewayType = uint8(i)
 	}
-	<-c // _BLANK
-	l = <-c
-	if i, e := strconv.Atoi(l.token); e != nil {
+	c.Next() // _BLANK
+	l = c.Next()
+	if i, e := strconv.ParseUint(l.token, 10, 8); e != nil || l.err {
 		return nil, &ParseError{f, "bad IPSECKEY Algorithm", l}, ""
 	} else {
 		rr.Algorithm = uint8(i)
 	}
-	<-c
-	l = <-c
+	c.Next()
+	l = c.Next()
+	if l.err {
+		return nil, &ParseError{f, "bad IPSECKEY Gateway", l}, ""
+	}
 	rr.Gateway = l.token
 	s, e, c1 := endingToString(c, "bad IPSECKEY PublicKey", f)
 	if e != nil {
@@ -1911,7 +2333,7 @@ func setIPSECKEY(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string)
 	return rr, nil, c1
 }
 
-func setDHCID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setDHCID(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	// awesome record to parse!
 	rr := new(DHCID)
 	rr.Hdr = h
@@ -1920,25 +2342,31 @@ func setDHCID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	if e != nil {
 		return nil, e, c1
 	}
+	if _, e := base64.StdEncoding.DecodeString(s); e != nil {
+		return nil, &ParseError{f, "bad DHCID Digest: " + e.Error(), lex{}}, ""
+	}
 	rr.Digest = s
 	return rr, nil, c1
 }
 
-func setNID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setNID(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(NID)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad NID Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if l.err {
+		return nil, &ParseError{f, "bad NID NodeID", l}, ""
+	}
 	u, err := stringToNodeID(l)
 	if err != nil {
 		return nil, err, ""
@@ -1947,21 +2375,24 @@ func setNID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setL32(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setL32(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(L32)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad L32 Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if l.err {
+		return nil, &ParseError{f, "bad L32 Locator", l}, ""
+	}
 	rr.Locator32 = net.ParseIP(l.token)
 	if rr.Locator32 == nil {
 		return nil, &ParseError{f, "bad L32 Locator", l}, ""
@@ -1969,26 +2400,32 @@ func setL32(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setLP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setLP(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(LP)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad LP Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if l.err {
+		return nil, &ParseError{f, "bad LP Fqdn", l}, ""
+	}
 	rr.Fqdn = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad LP Fqdn: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Fqdn = o
 		return rr, nil, ""
 	}
@@ -1997,26 +2434,32 @@ func setLP(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad LP Fqdn", l}, ""
 	}
 	if rr.Fqdn[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad LP Fqdn: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Fqdn = appendOrigin(rr.Fqdn, o)
 	}
 	return rr, nil, ""
 }
 
-func setL64(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setL64(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(L64)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad L64 Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if l.err {
+		return nil, &ParseError{f, "bad L64 Locator64", l}, ""
+	}
 	u, err := stringToNodeID(l)
 	if err != nil {
 		return nil, err, ""
@@ -2025,10 +2468,10 @@ func setL64(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setUID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setUID(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(UID)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -2040,10 +2483,10 @@ func setUID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setGID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setGID(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(GID)
 	rr.Hdr = h
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
@@ -2055,7 +2498,7 @@ func setGID(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, ""
 }
 
-func setUINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setUINFO(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(UINFO)
 	rr.Hdr = h
 	s, e, c1 := endingToTxtSlice(c, "bad UINFO Uinfo", f)
@@ -2066,26 +2509,32 @@ func setUINFO(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 	return rr, nil, c1
 }
 
-func setPX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
+func setPX(h RR_Header, c *zlexer, o, f string, strict bool) (RR, *ParseError, string) {
 	rr := new(PX)
 	rr.Hdr = h
 
-	l := <-c
+	l := c.Next()
 	if l.length == 0 {
 		return rr, nil, ""
 	}
-	if i, e := strconv.Atoi(l.token); e != nil {
+	if i, e := strconv.ParseUint(l.token, 10, 16); e != nil || l.err {
 		return nil, &ParseError{f, "bad PX Preference", l}, ""
 	} else {
 		rr.Preference = uint16(i)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
+	if l.err {
+		return nil, &ParseError{f, "bad PX Map822", l}, ""
+	}
 	rr.Map822 = l.token
 	if l.length == 0 {
 		return rr, nil, ""
 	}
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PX Map822: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Map822 = o
 		return rr, nil, ""
 	}
@@ -2094,12 +2543,18 @@ func setPX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad PX Map822", l}, ""
 	}
 	if rr.Map822[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PX Map822: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Map822 = appendOrigin(rr.Map822, o)
 	}
-	<-c     // _BLANK
-	l = <-c // _STRING
+	c.Next()     // _BLANK
+	l = c.Next() // _STRING
 	rr.Mapx400 = l.token
 	if l.token == "@" {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PX Mapx400: relative name (\"@\") requires an $ORIGIN", l}, ""
+		}
 		rr.Mapx400 = o
 		return rr, nil, ""
 	}
@@ -2108,6 +2563,9 @@ func setPX(h RR_Header, c chan lex, o, f string) (RR, *ParseError, string) {
 		return nil, &ParseError{f, "bad PX Mapx400", l}, ""
 	}
 	if rr.Mapx400[l.length-1] != '.' {
+		if o == "" && strict {
+			return nil, &ParseError{f, "bad PX Mapx400: relative name requires an $ORIGIN", l}, ""
+		}
 		rr.Mapx400 = appendOrigin(rr.Mapx400, o)
 	}
 	return rr, nil, ""
@@ -2166,6 +2624,8 @@ var typeToparserFunc = map[uint16]parserFunc{
 	TypeSPF:        parserFunc{setSPF, true},
 	TypeSRV:        parserFunc{setSRV, false},
 	TypeSSHFP:      parserFunc{setSSHFP, false},
+	TypeSVCB:       parserFunc{setSVCB, true},
+	TypeHTTPS:      parserFunc{setHTTPS, true},
 	TypeTALINK:     parserFunc{setTALINK, false},
 	TypeTA:         parserFunc{setTA, true},
 	TypeTLSA:       parserFunc{setTLSA, true},
@@ -2175,4 +2635,183 @@ var typeToparserFunc = map[uint16]parserFunc{
 	TypeURI:        parserFunc{setURI, true},
 	TypeWKS:        parserFunc{setWKS, true},
 	TypeX25:        parserFunc{setX25, false},
+	TypeZONEMD:     parserFunc{setZONEMD, true},
+}
+
+// strictSetRR is the entry point ParseZone uses when its Strict option is
+// enabled. It lives next to setRR (rather than in the tokenizer) so the two
+// stay in sync as RR types are added above.
+//
+// Note that only the per-RR origin checks threaded through the setters in
+// this file are enforced here; the companion requirement that every RR
+// carry a resolved TTL (from an explicit value, a prior RR, or $TTL, with
+// no fallback to the SOA MINIMUM per RFC 2308) is enforced by nextRRHeader
+// below, which is what actually reads the TTL field (or its absence) off
+// the token stream.
+func strictSetRR(h RR_Header, c *zlexer, o, f string) (RR, *ParseError, string) {
+	return setRR(h, c, o, f, true)
+}
+
+// nextRRHeader reads the next record's owner name, optional TTL and class,
+// and required type off c, transparently handling the $ORIGIN and $TTL
+// directives (and rejecting $INCLUDE unless includeAllowed) in between. It
+// returns eof == true once the input is exhausted with no more RRs to read.
+//
+// Per RFC 1035 Section 5.1 the TTL field may be omitted, in which case the
+// record inherits the most recently seen explicit TTL (from a prior RR or
+// a $TTL directive); per RFC 2308 Section 4 the SOA MINIMUM field must
+// never be used as that fallback. *defttl/*haveDefTTL hold that running
+// state across calls: haveDefTTL starts false (unless SetDefaultTTL seeded
+// it) and flips true the first time a $TTL directive or an explicit
+// per-RR TTL is seen; omitting the TTL before either has happened is a
+// *ParseError rather than a silent default.
+//
+// Owner-name elision (a blank first field reusing the previous RR's owner)
+// is not supported: every RR nextRRHeader reads must name its owner
+// explicitly, or use "@" for origin.
+func nextRRHeader(c *zlexer, origin *string, file string, defttl *uint32, haveDefTTL *bool, includeAllowed bool) (RR_Header, bool, *ParseError) {
+	var h RR_Header
+
+	l := c.Next()
+	for l.value == _NEWLINE {
+		l = c.Next()
+	}
+	if l.value == _EOF {
+		return h, true, nil
+	}
+	if l.err || l.value != _STRING {
+		return h, false, &ParseError{file, "bad RR owner", l}
+	}
+
+	switch strings.ToUpper(l.token) {
+	case "$ORIGIN":
+		c.Next() // _BLANK
+		ol := c.Next()
+		if ol.err || ol.value != _STRING {
+			return h, false, &ParseError{file, "bad $ORIGIN", ol}
+		}
+		if _, ok := IsDomainName(ol.token); !ok || len(ol.token) == 0 || ol.token[len(ol.token)-1] != '.' {
+			return h, false, &ParseError{file, "bad $ORIGIN: not fully qualified", ol}
+		}
+		*origin = ol.token
+		if e, _ := slurpRemainder(c, file); e != nil {
+			return h, false, e
+		}
+		return nextRRHeader(c, origin, file, defttl, haveDefTTL, includeAllowed)
+	case "$TTL":
+		c.Next() // _BLANK
+		tl := c.Next()
+		ttl, ok := stringToTtl(tl.token)
+		if tl.err || !ok {
+			return h, false, &ParseError{file, "bad $TTL", tl}
+		}
+		*defttl = ttl
+		*haveDefTTL = true
+		if e, _ := slurpRemainder(c, file); e != nil {
+			return h, false, e
+		}
+		return nextRRHeader(c, origin, file, defttl, haveDefTTL, includeAllowed)
+	case "$INCLUDE":
+		if !includeAllowed {
+			return h, false, &ParseError{file, "$INCLUDE is not allowed", l}
+		}
+		return h, false, &ParseError{file, "$INCLUDE is not supported by ZoneParser", l}
+	}
+
+	h.Name = l.token
+	if l.token == "@" {
+		h.Name = *origin
+	} else {
+		if _, ok := IsDomainName(l.token); !ok {
+			return h, false, &ParseError{file, "bad RR owner", l}
+		}
+		if len(h.Name) == 0 || h.Name[len(h.Name)-1] != '.' {
+			h.Name = appendOrigin(h.Name, *origin)
+		}
+	}
+
+	c.Next() // _BLANK
+	h.Class = ClassINET
+
+	haveTTL := false
+	l = c.Next()
+	if ttl, ok := stringToTtl(l.token); ok && !l.err {
+		h.Ttl = ttl
+		haveTTL = true
+		c.Next() // _BLANK
+		l = c.Next()
+	}
+	if cl, ok := StringToClass[l.tokenUpper]; ok {
+		h.Class = cl
+		c.Next() // _BLANK
+		l = c.Next()
+	}
+	if !haveTTL {
+		if ttl, ok := stringToTtl(l.token); ok && !l.err {
+			h.Ttl = ttl
+			haveTTL = true
+			c.Next() // _BLANK
+			l = c.Next()
+		}
+	}
+
+	if l.err || l.value != _STRING {
+		return h, false, &ParseError{file, "bad RR type", l}
+	}
+	t, ok := StringToType[l.tokenUpper]
+	if !ok {
+		if t, ok = typeToInt(l.tokenUpper); !ok {
+			return h, false, &ParseError{file, "bad RR type", l}
+		}
+	}
+	h.Rrtype = t
+
+	if !haveTTL {
+		if !*haveDefTTL {
+			return h, false, &ParseError{file, "missing TTL with no $TTL directive or prior RR TTL in scope", l}
+		}
+		h.Ttl = *defttl
+	} else {
+		// RFC 1035 Section 5.1: an RR's own explicit TTL becomes the
+		// default for any later RR that omits one, same as $TTL does.
+		*defttl = h.Ttl
+		*haveDefTTL = true
+	}
+
+	// Every setX function in this file starts by reading its first rdata
+	// field directly rather than a leading _BLANK, so consume the one
+	// separating the type field from rdata before handing off to it.
+	c.Next() // _BLANK
+
+	return h, false, nil
+}
+
+// Next returns the next RR in the zone, or ok == false once the zone is
+// exhausted or a parse error stopped the parser (check Err in that case).
+// It replaces ranging over ParseZone's "chan *Token" with the caller
+// driving consumption directly, but the tokenizer feeding zp is still a
+// goroutine blocked on a channel send under the hood; call Close if this
+// ZoneParser is abandoned before Next returns ok == false, or that
+// goroutine leaks.
+func (zp *ZoneParser) Next() (RR, bool) {
+	if zp.err != nil {
+		return nil, false
+	}
+	zp.zl.comments = nil
+	h, eof, e := nextRRHeader(zp.zl, &zp.origin, zp.file, &zp.defttl, &zp.haveTTL, zp.includeAllowed)
+	if e != nil {
+		zp.err = e
+		return nil, false
+	}
+	if eof {
+		return nil, false
+	}
+	rr, e, cm := setRR(h, zp.zl, zp.origin, zp.file, zp.strict)
+	if e != nil {
+		zp.err = e
+		return nil, false
+	}
+	zp.lastcomm = cm
+	zp.lastcomms = zp.zl.comments
+	return rr, true
 }
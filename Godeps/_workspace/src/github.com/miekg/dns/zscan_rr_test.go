@@ -0,0 +1,202 @@
+package dns
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// str builds a _STRING token carrying s, with tokenUpper and length filled
+// in the way the real tokenizer would.
+func str(s string) lex {
+	return lex{value: _STRING, token: s, tokenUpper: strings.ToUpper(s), length: len(s)}
+}
+
+// blank, newline and eof build the non-_STRING tokens nextRRHeader and the
+// setX functions expect in between the _STRING ones.
+func blank() lex                 { return lex{value: _BLANK} }
+func blankc(comment string) lex  { return lex{value: _BLANK, comment: comment} }
+func newline(comment string) lex { return lex{value: _NEWLINE, comment: comment} }
+func eof() lex                   { return lex{value: _EOF} }
+
+// feedLex returns a channel pre-loaded with ls, standing in for the
+// tokenizer goroutine that would normally produce them from zone text.
+func feedLex(ls ...lex) chan lex {
+	c := make(chan lex, len(ls))
+	for _, l := range ls {
+		c <- l
+	}
+	close(c)
+	return c
+}
+
+// TestComment checks that a trailing zone-file comment on an RR whose
+// setter reads its rdata through endingToString (here, DS) surfaces from
+// ZoneParser.Comment after the matching Next call.
+func TestComment(t *testing.T) {
+	c := feedLex(
+		str("example.com."), blank(),
+		str("3600"), blank(),
+		str("IN"), blank(),
+		str("DS"), blank(),
+		str("12345"), blank(),
+		str("8"), blank(),
+		str("2"), blank(),
+		str("ABCD1234"),
+		newline("; a trailing comment"),
+		eof(),
+	)
+
+	zp := NewZoneParser(c, ".", "TestComment")
+	rr, ok := zp.Next()
+	if !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	if _, ok := rr.(*DS); !ok {
+		t.Fatalf("got %T, want *DS", rr)
+	}
+	if got, want := zp.Comment(), "; a trailing comment"; got != want {
+		t.Errorf("Comment() = %q, want %q", got, want)
+	}
+	if _, ok := zp.Next(); ok {
+		t.Fatalf("expected no more RRs")
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestRegisterRRTypeRoundTrip registers a private-use type and checks it
+// parses through ZoneParser (the same table RegisterRRType feeds) and
+// shows up in RegisteredRRTypes, then that UnregisterRRType removes it
+// again.
+func TestRegisterRRTypeRoundTrip(t *testing.T) {
+	const rrtype = 65500
+	const name = "TYPE65500"
+
+	if err := RegisterPrivateRR(rrtype, name, func() RR { return new(RFC3597) }); err != nil {
+		t.Fatalf("RegisterPrivateRR: %v", err)
+	}
+	defer UnregisterRRType(rrtype)
+
+	found := false
+	for _, rt := range RegisteredRRTypes() {
+		if rt == rrtype {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("RegisteredRRTypes() does not contain %d after registering it", rrtype)
+	}
+
+	c := feedLex(
+		str("x.example.com."), blank(),
+		str("3600"), blank(),
+		str("IN"), blank(),
+		str(name), blank(),
+		str(`\#`), blank(),
+		str("2"), blank(),
+		str("abcd"),
+		newline(""),
+		eof(),
+	)
+
+	zp := NewZoneParser(c, ".", "TestRegisterRRTypeRoundTrip")
+	rr, ok := zp.Next()
+	if !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	rfc3597, ok := rr.(*RFC3597)
+	if !ok {
+		t.Fatalf("got %T, want *RFC3597", rr)
+	}
+	if rfc3597.Hdr.Rrtype != rrtype {
+		t.Errorf("Rrtype = %d, want %d", rfc3597.Hdr.Rrtype, rrtype)
+	}
+	if rfc3597.Rdata != "abcd" {
+		t.Errorf("Rdata = %q, want %q", rfc3597.Rdata, "abcd")
+	}
+}
+
+// TestZoneParserCloseUnblocksTokenizer checks that abandoning a ZoneParser
+// before Next returns ok == false doesn't leak the tokenizer goroutine
+// feeding it: on an unbuffered channel, its next send blocks until
+// something reads it, and Close must be what does that once the caller
+// stops calling Next.
+func TestZoneParserCloseUnblocksTokenizer(t *testing.T) {
+	c := make(chan lex)
+	sent := make(chan struct{})
+	go func() {
+		for _, l := range []lex{
+			str("example.com."), blank(),
+			str("3600"), blank(),
+			str("IN"), blank(),
+			str("A"), blank(),
+			str("192.0.2.1"),
+		} {
+			c <- l
+		}
+		// Next only reads as far as setA's rdata; this send has nothing
+		// left to drain it once the caller below stops calling Next, and
+		// blocks forever without Close.
+		c <- str("abandoned.example.com.")
+		close(sent)
+	}()
+
+	zp := NewZoneParser(c, ".", "TestZoneParserCloseUnblocksTokenizer")
+	if _, ok := zp.Next(); !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	zp.Close()
+
+	select {
+	case <-sent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("tokenizer goroutine still blocked on send after Close")
+	}
+}
+
+// TestCommentsMultiLine checks that ZoneParser.Comments surfaces every
+// comment seen while parsing one RR's parenthesised, multi-line rdata —
+// before, in between and after its fields — rather than only the single
+// trailing one Comment reports, and that each is attributed to the right
+// RR.
+func TestCommentsMultiLine(t *testing.T) {
+	c := feedLex(
+		str("example.com."), blank(),
+		str("3600"), blank(),
+		str("IN"), blank(),
+		str("SOA"), blank(),
+		str("ns1.example.com."), blankc("; primary"),
+		str("hostmaster.example.com."), blankc("; contact"),
+		str("2026072601"), blankc("; serial"),
+		str("3600"), blank(),
+		str("900"), blank(),
+		str("604800"), blank(),
+		str("86400"),
+		newline("; trailing"),
+		eof(),
+	)
+
+	zp := NewZoneParser(c, ".", "TestCommentsMultiLine")
+	rr, ok := zp.Next()
+	if !ok {
+		t.Fatalf("Next failed: %v", zp.Err())
+	}
+	if _, ok := rr.(*SOA); !ok {
+		t.Fatalf("got %T, want *SOA", rr)
+	}
+
+	want := []string{"; primary", "; contact", "; serial", "; trailing"}
+	if got := zp.Comments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Comments() = %v, want %v", got, want)
+	}
+	if got, want := zp.Comment(), "; trailing"; got != want {
+		t.Errorf("Comment() = %q, want %q", got, want)
+	}
+	if _, ok := zp.Next(); ok {
+		t.Fatalf("expected no more RRs")
+	}
+}